@@ -98,11 +98,10 @@ func runChat(cmd *cobra.Command, args []string) {
 			messages = messages[:len(messages)-1]
 			continue
 		}
-		defer stream.Close()
 
 		fmt.Print("\n< AI: ")
 
-		var fullResponseContent strings.Builder
+		acc := openrouter.NewStreamAccumulator()
 
 		// 3. Process the streaming response
 		for {
@@ -113,27 +112,21 @@ func runChat(cmd *cobra.Command, args []string) {
 				}
 				break
 			}
+			acc.Add(resp)
 
 			// In a streaming response, the content is in the Delta field
 			if len(resp.Choices) > 0 && resp.Choices[0].Delta != nil {
-				content := resp.Choices[0].Delta.Content // Content is string for simple text
-
-				// Handle both string and array content if necessary, but typically
-				// streamed text is a string from OpenRouter/OpenAI compatible APIs.
-				if contentStr, ok := content.(string); ok && contentStr != "" {
+				if contentStr, ok := resp.Choices[0].Delta.Content.(string); ok {
 					fmt.Print(contentStr)
-					fullResponseContent.WriteString(contentStr)
 				}
 			}
 		}
+		stream.Close()
 
 		// 4. Add the full AI response to history
-		aiContent := fullResponseContent.String()
-		if aiContent != "" {
-			messages = append(messages, openrouter.ChatMessage{
-				Role:    "assistant",
-				Content: aiContent,
-			})
+		full := acc.Response()
+		if len(full.Choices) > 0 && full.Choices[0].Message.Content != "" {
+			messages = append(messages, *full.Choices[0].Message)
 		}
 
 		fmt.Println() // Newline after AI response is complete
@@ -0,0 +1,348 @@
+package jsonschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// Reflector generates schemas from Go types.
+type Reflector struct {
+	// Strict produces schemas accepted by strict structured outputs: every
+	// property is listed as required and optional ones become nullable. Maps
+	// are rejected, since strict mode requires additionalProperties: false.
+	Strict bool
+}
+
+// Reflect generates the schema for the type of v.
+func Reflect(v interface{}) (*Schema, error) {
+	return (&Reflector{}).Reflect(v)
+}
+
+// For generates the schema for T.
+func For[T any]() (*Schema, error) {
+	return (&Reflector{}).ReflectType(reflect.TypeFor[T]())
+}
+
+// Reflect generates the schema for the type of v.
+func (r *Reflector) Reflect(v interface{}) (*Schema, error) {
+	if v == nil {
+		return nil, fmt.Errorf("jsonschema: cannot reflect nil")
+	}
+	return r.ReflectType(reflect.TypeOf(v))
+}
+
+// ReflectType generates the schema for t.
+func (r *Reflector) ReflectType(t reflect.Type) (*Schema, error) {
+	g := &generator{
+		strict:    r.Strict,
+		defs:      make(map[string]*Schema),
+		visiting:  make(map[reflect.Type]bool),
+		recursive: make(map[reflect.Type]bool),
+	}
+
+	s, err := g.schema(t)
+	if err != nil {
+		return nil, err
+	}
+
+	// A recursive root type is returned inline; references resolve via $defs.
+	if s.Ref != "" {
+		root := *g.defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		s = &root
+	}
+	if len(g.defs) > 0 {
+		s.Defs = g.defs
+	}
+
+	return s, nil
+}
+
+// -----------------------------------------------------------------------------
+// Internal Helpers
+// -----------------------------------------------------------------------------
+
+type generator struct {
+	strict    bool
+	defs      map[string]*Schema
+	visiting  map[reflect.Type]bool
+	recursive map[reflect.Type]bool
+}
+
+func (g *generator) schema(t reflect.Type) (*Schema, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}, nil
+	case rawMessageType:
+		return &Schema{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", ContentEncoding: "base64"}, nil
+		}
+		items, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		s := &Schema{Type: "array", Items: items}
+		if t.Kind() == reflect.Array {
+			n := t.Len()
+			s.MinItems, s.MaxItems = &n, &n
+		}
+		return s, nil
+	case reflect.Map:
+		if g.strict {
+			return nil, fmt.Errorf("jsonschema: map type %s is not supported in strict mode", t)
+		}
+		if t.Key().Kind() != reflect.String && !t.Key().Implements(textMarshalerType) {
+			return nil, fmt.Errorf("jsonschema: unsupported map key type %s", t.Key())
+		}
+		values, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return g.structSchema(t)
+	}
+
+	return nil, fmt.Errorf("jsonschema: unsupported type %s", t)
+}
+
+func (g *generator) structSchema(t reflect.Type) (*Schema, error) {
+	ref := &Schema{Ref: "#/$defs/" + defName(t)}
+	if g.visiting[t] {
+		g.recursive[t] = true
+		return ref, nil
+	}
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+
+	s := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+	if err := g.addFields(s, t); err != nil {
+		return nil, err
+	}
+
+	if g.recursive[t] {
+		g.defs[defName(t)] = s
+		return ref, nil
+	}
+	return s, nil
+}
+
+// addFields adds the fields of struct type t to s, flattening embedded structs
+// the way encoding/json does.
+func (g *generator) addFields(s *Schema, t reflect.Type) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, jsonOpts, _ := strings.Cut(tag, ",")
+
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			if err := g.addFields(s, ft); err != nil {
+				return err
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fs, err := g.schema(f.Type)
+		if err != nil {
+			return fmt.Errorf("jsonschema: field %s: %w", f.Name, err)
+		}
+
+		required := f.Type.Kind() != reflect.Pointer && !hasOption(jsonOpts, "omitempty")
+		if required, err = applyTag(fs, f.Tag.Get("jsonschema"), required); err != nil {
+			return fmt.Errorf("jsonschema: field %s: %w", f.Name, err)
+		}
+
+		if g.strict && !required {
+			fs = &Schema{
+				Description: fs.Description,
+				AnyOf:       []*Schema{fs, {Type: "null"}},
+			}
+			fs.AnyOf[0].Description = ""
+			required = true
+		}
+
+		s.Properties[name] = fs
+		if required {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return nil
+}
+
+// applyTag applies the options of a `jsonschema` tag to s and returns whether
+// the field is required.
+func applyTag(s *Schema, tag string, required bool) (bool, error) {
+	if tag == "" {
+		return required, nil
+	}
+
+	// Enum and default values apply to the items of an array.
+	target := s
+	if s.Type == "array" && s.Items != nil {
+		target = s.Items
+	}
+
+	for _, opt := range splitTag(tag) {
+		key, value, _ := strings.Cut(opt, "=")
+		var err error
+		switch strings.TrimSpace(key) {
+		case "required":
+			required = true
+		case "optional":
+			required = false
+		case "description":
+			s.Description = value
+		case "format":
+			s.Format = value
+		case "pattern":
+			s.Pattern = value
+		case "enum":
+			for _, v := range strings.Split(value, "|") {
+				parsed, perr := parseValue(target.Type, v)
+				if perr != nil {
+					return required, perr
+				}
+				target.Enum = append(target.Enum, parsed)
+			}
+		case "default":
+			s.Default, err = parseValue(s.Type, value)
+		case "minimum":
+			s.Minimum, err = parseFloat(value)
+		case "maximum":
+			s.Maximum, err = parseFloat(value)
+		case "minLength":
+			s.MinLength, err = parseInt(value)
+		case "maxLength":
+			s.MaxLength, err = parseInt(value)
+		case "minItems":
+			s.MinItems, err = parseInt(value)
+		case "maxItems":
+			s.MaxItems, err = parseInt(value)
+		default:
+			return required, fmt.Errorf("unknown jsonschema option %q", key)
+		}
+		if err != nil {
+			return required, fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+
+	return required, nil
+}
+
+// splitTag splits a tag on commas that are not escaped with a backslash.
+func splitTag(tag string) []string {
+	var (
+		opts []string
+		cur  strings.Builder
+	)
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			cur.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			opts = append(opts, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(tag[i])
+		}
+	}
+	return append(opts, cur.String())
+}
+
+// parseValue converts a tag value to the JSON type of the schema.
+func parseValue(typ, v string) (interface{}, error) {
+	switch typ {
+	case "integer":
+		return strconv.ParseInt(v, 10, 64)
+	case "number":
+		return strconv.ParseFloat(v, 64)
+	case "boolean":
+		return strconv.ParseBool(v)
+	}
+	return v, nil
+}
+
+func parseFloat(v string) (*float64, error) {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func parseInt(v string) (*int, error) {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func hasOption(opts, name string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
+
+// defName returns the $defs key for a named type.
+func defName(t reflect.Type) string {
+	if t.Name() != "" {
+		return t.Name()
+	}
+	return strings.NewReplacer(" ", "", "{", "_", "}", "_", ";", "_").Replace(t.String())
+}
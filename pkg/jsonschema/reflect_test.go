@@ -0,0 +1,118 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type Node struct {
+	Name     string `json:"name"`
+	Children []Node `json:"children,omitempty"`
+}
+
+type profile struct {
+	Name  string   `json:"name" jsonschema:"description=Full name\\, as written"`
+	Nick  *string  `json:"nick" jsonschema:"description=Nickname"`
+	Tags  []string `json:"tags" jsonschema:"enum=red|green"`
+	Email string   `json:"email,omitempty" jsonschema:"format=email"`
+}
+
+func TestReflectRecursive(t *testing.T) {
+	s, err := For[Node]()
+	if err != nil {
+		t.Fatalf("For: %v", err)
+	}
+
+	if s.Type != "object" || s.Properties["name"] == nil {
+		t.Fatalf("root is not inlined: %+v", s)
+	}
+	def, ok := s.Defs["Node"]
+	if !ok {
+		t.Fatalf("missing $defs/Node in %+v", s.Defs)
+	}
+	if ref := s.Properties["children"].Items.Ref; ref != "#/$defs/Node" {
+		t.Errorf("children items $ref = %q", ref)
+	}
+	if ref := def.Properties["children"].Items.Ref; ref != "#/$defs/Node" {
+		t.Errorf("definition children items $ref = %q", ref)
+	}
+}
+
+func TestReflectStrict(t *testing.T) {
+	s, err := (&Reflector{Strict: true}).Reflect(profile{})
+	if err != nil {
+		t.Fatalf("Reflect: %v", err)
+	}
+
+	if want := []string{"name", "nick", "tags", "email"}; !reflect.DeepEqual(s.Required, want) {
+		t.Errorf("required = %v, want %v", s.Required, want)
+	}
+	if s.AdditionalProperties != false {
+		t.Errorf("additionalProperties = %v, want false", s.AdditionalProperties)
+	}
+
+	nick := s.Properties["nick"]
+	if nick.Description != "Nickname" || len(nick.AnyOf) != 2 ||
+		nick.AnyOf[0].Type != "string" || nick.AnyOf[0].Description != "" || nick.AnyOf[1].Type != "null" {
+		t.Errorf("nick = %s, want a described anyOf of string and null", marshal(t, nick))
+	}
+
+	email := s.Properties["email"]
+	if len(email.AnyOf) != 2 || email.AnyOf[0].Format != "email" {
+		t.Errorf("email = %s, want a nullable string with format email", marshal(t, email))
+	}
+
+	if desc := s.Properties["name"].Description; desc != "Full name, as written" {
+		t.Errorf("name description = %q", desc)
+	}
+
+	tags := s.Properties["tags"]
+	if tags.Enum != nil || !reflect.DeepEqual(tags.Items.Enum, []interface{}{"red", "green"}) {
+		t.Errorf("tags = %s, want the enum on the items", marshal(t, tags))
+	}
+}
+
+func TestReflectStrictRejectsMaps(t *testing.T) {
+	type withMap struct {
+		Scores map[string]int `json:"scores"`
+	}
+
+	if _, err := (&Reflector{Strict: true}).Reflect(withMap{}); err == nil {
+		t.Error("strict reflection of a map field: expected an error")
+	}
+
+	s, err := Reflect(withMap{})
+	if err != nil {
+		t.Fatalf("Reflect: %v", err)
+	}
+	if values, ok := s.Properties["scores"].AdditionalProperties.(*Schema); !ok || values.Type != "integer" {
+		t.Errorf("scores = %s, want integer additionalProperties", marshal(t, s.Properties["scores"]))
+	}
+}
+
+func TestSplitTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want []string
+	}{
+		{"required", []string{"required"}},
+		{"description=a\\, b,format=email", []string{"description=a, b", "format=email"}},
+		{"description=trailing\\", []string{"description=trailing\\"}},
+		{"a,,b", []string{"a", "", "b"}},
+	}
+	for _, tt := range tests {
+		if got := splitTag(tt.tag); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitTag(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func marshal(t *testing.T, s *Schema) string {
+	t.Helper()
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,57 @@
+// Package jsonschema generates JSON Schema documents from Go types, for use in
+// tool parameters and structured output response formats.
+//
+// Field names follow `json` tags. Non-pointer fields without omitempty are
+// required. The `jsonschema` tag accepts comma-separated options:
+//
+//	description=...   field description (escape commas as \,)
+//	enum=a|b|c        allowed values (enum= may also be repeated)
+//	format=...        string format (e.g. "email", "uri")
+//	pattern=...       regular expression for strings
+//	minimum=, maximum=, minLength=, maxLength=, minItems=, maxItems=
+//	default=...       default value
+//	required          force the field to be required
+//	optional          force the field to be optional
+package jsonschema
+
+import "encoding/json"
+
+// Schema is a JSON Schema document or subschema.
+type Schema struct {
+	Ref         string `json:"$ref,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Format      string `json:"format,omitempty"`
+
+	// Object keywords
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"` // bool or *Schema
+
+	// Array keywords
+	Items    *Schema `json:"items,omitempty"`
+	MinItems *int    `json:"minItems,omitempty"`
+	MaxItems *int    `json:"maxItems,omitempty"`
+
+	// String keywords
+	Pattern         string `json:"pattern,omitempty"`
+	MinLength       *int   `json:"minLength,omitempty"`
+	MaxLength       *int   `json:"maxLength,omitempty"`
+	ContentEncoding string `json:"contentEncoding,omitempty"`
+
+	// Numeric keywords
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+
+	Enum    []interface{} `json:"enum,omitempty"`
+	Default interface{}   `json:"default,omitempty"`
+	AnyOf   []*Schema     `json:"anyOf,omitempty"`
+
+	// Definitions of recursive types, referenced via "#/$defs/<name>".
+	Defs map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// RawMessage marshals the schema for use in openrouter.ToolFunction.Parameters.
+func (s *Schema) RawMessage() (json.RawMessage, error) {
+	return json.Marshal(s)
+}
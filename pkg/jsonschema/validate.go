@@ -0,0 +1,198 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError describes the first place a value failed to match a schema.
+type ValidationError struct {
+	// JSON Pointer to the offending value (e.g. "/items/0/name").
+	Path string
+
+	// What was wrong.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("%s: %s", path, e.Message)
+}
+
+// ValidateJSON decodes data and validates it against s.
+func (s *Schema) ValidateJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return &ValidationError{Message: fmt.Sprintf("invalid JSON: %v", err)}
+	}
+	return s.Validate(v)
+}
+
+// Validate checks a value decoded by encoding/json (maps, slices, float64,
+// string, bool and nil) against s. It supports the keywords produced by this
+// package.
+func (s *Schema) Validate(v interface{}) error {
+	return s.validate(v, "", s.Defs)
+}
+
+func (s *Schema) validate(v interface{}, path string, defs map[string]*Schema) error {
+	fail := func(format string, args ...interface{}) error {
+		return &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)}
+	}
+
+	if s.Ref != "" {
+		def, ok := defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		if !ok {
+			return fail("unresolved reference %s", s.Ref)
+		}
+		return def.validate(v, path, defs)
+	}
+
+	if len(s.AnyOf) > 0 {
+		var firstErr error
+		for _, sub := range s.AnyOf {
+			err := sub.validate(v, path, defs)
+			if err == nil {
+				firstErr = nil
+				break
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		if firstErr != nil {
+			return firstErr
+		}
+	}
+
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e interface{}) bool { return jsonEqual(e, v) }) {
+		return fail("value %v is not one of %v", v, s.Enum)
+	}
+
+	switch s.Type {
+	case "":
+		return nil
+	case "null":
+		if v != nil {
+			return fail("expected null")
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fail("expected boolean")
+		}
+	case "integer", "number":
+		n, ok := v.(float64)
+		if !ok {
+			return fail("expected %s", s.Type)
+		}
+		if s.Type == "integer" && n != math.Trunc(n) {
+			return fail("expected integer, got %v", n)
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			return fail("%v is less than minimum %v", n, *s.Minimum)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			return fail("%v is greater than maximum %v", n, *s.Maximum)
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fail("expected string")
+		}
+		length := utf8.RuneCountInString(str)
+		if s.MinLength != nil && length < *s.MinLength {
+			return fail("string is shorter than %d", *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			return fail("string is longer than %d", *s.MaxLength)
+		}
+		if s.Pattern != "" {
+			re, err := regexp.Compile(s.Pattern)
+			if err != nil {
+				return fail("invalid pattern %q: %v", s.Pattern, err)
+			}
+			if !re.MatchString(str) {
+				return fail("string does not match pattern %q", s.Pattern)
+			}
+		}
+	case "array":
+		arr, ok := v.([]interface{})
+		if !ok {
+			return fail("expected array")
+		}
+		if s.MinItems != nil && len(arr) < *s.MinItems {
+			return fail("array has fewer than %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(arr) > *s.MaxItems {
+			return fail("array has more than %d items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range arr {
+				if err := s.Items.validate(item, fmt.Sprintf("%s/%d", path, i), defs); err != nil {
+					return err
+				}
+			}
+		}
+	case "object":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return fail("expected object")
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				return fail("missing required property %q", name)
+			}
+		}
+		for _, name := range sortedKeys(obj) {
+			child := path + "/" + name
+			if prop, ok := s.Properties[name]; ok {
+				if err := prop.validate(obj[name], child, defs); err != nil {
+					return err
+				}
+				continue
+			}
+			switch extra := s.AdditionalProperties.(type) {
+			case bool:
+				if !extra {
+					return fail("unexpected property %q", name)
+				}
+			case *Schema:
+				if err := extra.validate(obj[name], child, defs); err != nil {
+					return err
+				}
+			}
+		}
+	default:
+		return fail("unsupported schema type %q", s.Type)
+	}
+
+	return nil
+}
+
+// jsonEqual compares an enum value from a tag with a decoded JSON value.
+func jsonEqual(a, b interface{}) bool {
+	switch x := a.(type) {
+	case int64:
+		f, ok := b.(float64)
+		return ok && f == float64(x)
+	}
+	return a == b
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,51 @@
+package jsonschema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	node, err := For[Node]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	strict, err := (&Reflector{Strict: true}).Reflect(profile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		schema  *Schema
+		input   string
+		errPath string // empty when valid
+	}{
+		{"recursive valid", node, `{"name":"a","children":[{"name":"b","children":[{"name":"c"}]}]}`, ""},
+		{"recursive invalid", node, `{"name":"a","children":[{"name":"b","children":[{"name":1}]}]}`, "/children/0/children/0/name"},
+		{"recursive extra property", node, `{"name":"a","children":[{"name":"b","x":1}]}`, "/children/0"},
+		{"nullable null", strict, `{"name":"a","nick":null,"tags":[],"email":null}`, ""},
+		{"nullable value", strict, `{"name":"a","nick":"b","tags":["red"],"email":"a@b.c"}`, ""},
+		{"nullable wrong type", strict, `{"name":"a","nick":1,"tags":[],"email":null}`, "/nick"},
+		{"missing required", strict, `{"name":"a","tags":[],"email":null}`, "/"},
+		{"enum item", strict, `{"name":"a","nick":null,"tags":["red","blue"],"email":null}`, "/tags/1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schema.ValidateJSON([]byte(tt.input))
+			if tt.errPath == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.HasPrefix(err.Error(), tt.errPath+":") {
+				t.Errorf("error = %q, want it at %s", err, tt.errPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package openrouter
+
+import (
+	"context"
+	"net/http"
+)
+
+// GetCredits retrieves the total credits purchased and used by the account.
+func (c *Client) GetCredits(ctx context.Context) (*Credits, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/credits", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CreditsResponse
+	if err := c.sendRequest(req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Data, nil
+}
+
+// GetKeyInfo retrieves usage, limits and rate limits of the client's API key.
+func (c *Client) GetKeyInfo(ctx context.Context) (*KeyInfo, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/key", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp KeyInfoResponse
+	if err := c.sendRequest(req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Data, nil
+}
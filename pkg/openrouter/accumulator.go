@@ -0,0 +1,253 @@
+package openrouter
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"strings"
+)
+
+// StreamAccumulator merges streamed chunks into a single ChatCompletionResponse
+// shaped like the one returned by CreateChatCompletion.
+type StreamAccumulator struct {
+	resp    ChatCompletionResponse
+	choices map[int]*choiceState
+}
+
+// choiceState holds the partially assembled state of one choice.
+type choiceState struct {
+	role         string
+	content      strings.Builder
+	reasoning    strings.Builder
+	details      map[int]*ReasoningDetail
+	annotations  []Annotation
+	toolCalls    map[int]*toolCallState
+	finishReason string
+}
+
+type toolCallState struct {
+	id        string
+	typ       string
+	name      string
+	arguments strings.Builder
+}
+
+// NewStreamAccumulator returns an empty accumulator.
+func NewStreamAccumulator() *StreamAccumulator {
+	return &StreamAccumulator{choices: make(map[int]*choiceState)}
+}
+
+// Add merges a single streamed chunk.
+func (a *StreamAccumulator) Add(chunk *ChatCompletionResponse) {
+	if chunk == nil {
+		return
+	}
+
+	if chunk.ID != "" {
+		a.resp.ID = chunk.ID
+	}
+	if chunk.Created != 0 {
+		a.resp.Created = chunk.Created
+	}
+	if chunk.Model != "" {
+		a.resp.Model = chunk.Model
+	}
+	if chunk.Provider != "" {
+		a.resp.Provider = chunk.Provider
+	}
+	if chunk.SystemFingerprint != "" {
+		a.resp.SystemFingerprint = chunk.SystemFingerprint
+	}
+	if chunk.Usage != nil {
+		usage := *chunk.Usage
+		a.resp.Usage = &usage
+	}
+
+	for _, c := range chunk.Choices {
+		state, ok := a.choices[c.Index]
+		if !ok {
+			state = &choiceState{
+				details:   make(map[int]*ReasoningDetail),
+				toolCalls: make(map[int]*toolCallState),
+			}
+			a.choices[c.Index] = state
+		}
+		if c.FinishReason != "" {
+			state.finishReason = c.FinishReason
+		}
+
+		delta := c.Delta
+		if delta == nil {
+			delta = c.Message
+		}
+		if delta == nil {
+			continue
+		}
+
+		if delta.Role != "" {
+			state.role = delta.Role
+		}
+		if text, ok := delta.Content.(string); ok {
+			state.content.WriteString(text)
+		}
+		state.reasoning.WriteString(delta.Reasoning)
+		state.annotations = append(state.annotations, delta.Annotations...)
+		for i, d := range delta.ReasoningDetails {
+			state.addReasoningDetail(i, d)
+		}
+		for i, tc := range delta.ToolCalls {
+			state.addToolCall(i, tc)
+		}
+	}
+}
+
+// addReasoningDetail merges a reasoning block fragment by its index, falling
+// back to its position in the delta.
+func (s *choiceState) addReasoningDetail(pos int, d ReasoningDetail) {
+	idx := pos
+	if d.Index != nil {
+		idx = *d.Index
+	}
+
+	detail, ok := s.details[idx]
+	if !ok {
+		detail = &ReasoningDetail{}
+		s.details[idx] = detail
+	}
+	if d.Type != "" {
+		detail.Type = d.Type
+	}
+	if d.ID != "" {
+		detail.ID = d.ID
+	}
+	if d.Format != "" {
+		detail.Format = d.Format
+	}
+	if d.Signature != "" {
+		detail.Signature = d.Signature
+	}
+	if d.Index != nil {
+		detail.Index = d.Index
+	}
+	detail.Text += d.Text
+	detail.Summary += d.Summary
+	detail.Data += d.Data
+}
+
+// addToolCall merges a tool call fragment. Fragments are matched by their
+// index, falling back to the ID; anonymous fragments continue the latest call.
+func (s *choiceState) addToolCall(pos int, tc ToolCall) {
+	idx := pos
+	switch {
+	case tc.Index != nil:
+		idx = *tc.Index
+	case tc.ID != "":
+		idx = len(s.toolCalls)
+		for i, existing := range s.toolCalls {
+			if existing.id == tc.ID {
+				idx = i
+				break
+			}
+		}
+	case len(s.toolCalls) > 0:
+		keys := sortedKeys(s.toolCalls)
+		idx = keys[len(keys)-1]
+	}
+
+	call, ok := s.toolCalls[idx]
+	if !ok {
+		call = &toolCallState{}
+		s.toolCalls[idx] = call
+	}
+	if tc.ID != "" {
+		call.id = tc.ID
+	}
+	if tc.Type != "" {
+		call.typ = tc.Type
+	}
+	if tc.Function.Name != "" {
+		call.name = tc.Function.Name
+	}
+	call.arguments.WriteString(tc.Function.Arguments)
+}
+
+// Response returns the response assembled from the chunks added so far.
+func (a *StreamAccumulator) Response() *ChatCompletionResponse {
+	resp := a.resp
+	resp.Object = "chat.completion"
+	resp.Choices = make([]Choice, 0, len(a.choices))
+
+	for _, index := range sortedKeys(a.choices) {
+		state := a.choices[index]
+
+		role := state.role
+		if role == "" {
+			role = "assistant"
+		}
+		msg := &ChatMessage{
+			Role:        role,
+			Content:     state.content.String(),
+			Reasoning:   state.reasoning.String(),
+			Annotations: state.annotations,
+		}
+
+		for _, i := range sortedKeys(state.details) {
+			msg.ReasoningDetails = append(msg.ReasoningDetails, *state.details[i])
+		}
+
+		for _, i := range sortedKeys(state.toolCalls) {
+			call := state.toolCalls[i]
+			typ := call.typ
+			if typ == "" {
+				typ = "function"
+			}
+			msg.ToolCalls = append(msg.ToolCalls, ToolCall{
+				ID:   call.id,
+				Type: typ,
+				Function: ToolCallFunction{
+					Name:      call.name,
+					Arguments: call.arguments.String(),
+				},
+			})
+		}
+
+		resp.Choices = append(resp.Choices, Choice{
+			Index:        index,
+			Message:      msg,
+			FinishReason: state.finishReason,
+		})
+	}
+
+	return &resp
+}
+
+// Collect reads the rest of the stream and returns the assembled response.
+// On a read error, including a *StreamError, the response assembled so far is
+// returned with the error.
+// The stream is not closed.
+func (s *ChatCompletionStream) Collect() (*ChatCompletionResponse, error) {
+	acc := NewStreamAccumulator()
+	for {
+		chunk, err := s.Recv()
+		if err == io.EOF {
+			return acc.Response(), nil
+		}
+		if err != nil {
+			var streamErr *StreamError
+			if errors.As(err, &streamErr) {
+				acc.Add(streamErr.Response)
+			}
+			return acc.Response(), err
+		}
+		acc.Add(chunk)
+	}
+}
+
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,231 @@
+package openrouter
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// defaultBatchConcurrency is used when BatchRunner.Concurrency is unset.
+const defaultBatchConcurrency = 4
+
+// ErrSpendCapReached is set on batch items that were not sent because the
+// accumulated cost reached BatchRunner.MaxCost.
+var ErrSpendCapReached = errors.New("batch runner: spend cap reached")
+
+// BatchRunner sends many chat completion requests with bounded concurrency.
+type BatchRunner struct {
+	client *Client
+
+	// Maximum number of requests in flight (defaults to 4). It also bounds
+	// how far the batch runs ahead of the oldest item not yet delivered, so a
+	// slow item holds back later ones instead of letting results pile up.
+	Concurrency int
+
+	// Maximum accumulated cost of the batch in USD; zero means no limit.
+	// Setting it turns on usage accounting for the requests. Requests already
+	// in flight when the cap is reached still complete, so the total may
+	// exceed it by the cost of up to Concurrency requests.
+	MaxCost float64
+
+	// Optional hook called after each item completes. Calls are serialized.
+	OnProgress func(BatchProgress)
+}
+
+// BatchResult is the outcome of one request of a batch.
+type BatchResult struct {
+	// Position of the request in the input.
+	Index int
+
+	// The response, or nil on error.
+	Response *ChatCompletionResponse
+
+	// The error of this item: an API error, a context error, or
+	// ErrSpendCapReached.
+	Err error
+}
+
+// BatchProgress reports the state of a running batch.
+type BatchProgress struct {
+	// The item that just completed.
+	Result BatchResult
+
+	// Number of items completed so far, including failed ones.
+	Completed int
+
+	// Number of items that failed.
+	Failed int
+
+	// Number of items in the batch, or -1 when reading from a channel.
+	Total int
+
+	// Accumulated cost reported in usage, in USD.
+	Cost float64
+}
+
+// NewBatchRunner creates a BatchRunner that sends requests through client.
+func NewBatchRunner(client *Client) *BatchRunner {
+	return &BatchRunner{client: client}
+}
+
+// Run sends requests and returns one result per request, in input order.
+// Failed items do not stop the batch; their errors are kept in the results.
+// If ctx is canceled, items not yet completed get the context error and Run
+// returns it. If the spend cap is reached, Run returns ErrSpendCapReached.
+func (r *BatchRunner) Run(ctx context.Context, requests []ChatCompletionRequest) ([]BatchResult, error) {
+	in := make(chan ChatCompletionRequest)
+	go func() {
+		defer close(in)
+		for _, req := range requests {
+			select {
+			case in <- req:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	results := make([]BatchResult, len(requests))
+	done := make([]bool, len(requests))
+	capped := false
+	r.run(ctx, in, len(requests), func(res BatchResult) {
+		results[res.Index] = res
+		done[res.Index] = true
+		capped = capped || errors.Is(res.Err, ErrSpendCapReached)
+	})
+
+	for i := range results {
+		if !done[i] {
+			results[i] = BatchResult{Index: i, Err: ctx.Err()}
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return results, err
+	}
+	if capped {
+		return results, ErrSpendCapReached
+	}
+	return results, nil
+}
+
+// Stream sends the requests received from requests until it is closed and
+// delivers the results in input order. The returned channel is closed when
+// all results have been delivered; callers must drain it. If ctx is
+// canceled, Stream stops reading requests.
+func (r *BatchRunner) Stream(ctx context.Context, requests <-chan ChatCompletionRequest) <-chan BatchResult {
+	out := make(chan BatchResult)
+	go func() {
+		defer close(out)
+		r.run(ctx, requests, -1, func(res BatchResult) {
+			out <- res
+		})
+	}()
+	return out
+}
+
+// -----------------------------------------------------------------------------
+// Internal Helpers
+// -----------------------------------------------------------------------------
+
+// run dispatches requests to workers and passes their results to emit in
+// input order.
+func (r *BatchRunner) run(ctx context.Context, requests <-chan ChatCompletionRequest, total int, emit func(BatchResult)) {
+	concurrency := r.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	results := make(chan BatchResult, concurrency)
+	sem := make(chan struct{}, concurrency)
+
+	var mu sync.Mutex
+	progress := BatchProgress{Total: total}
+	finish := func(res BatchResult) {
+		mu.Lock()
+		progress.Result = res
+		progress.Completed++
+		if res.Err != nil {
+			progress.Failed++
+		}
+		if res.Response != nil {
+			progress.Cost += res.Response.Usage.BilledCost()
+		}
+		if r.OnProgress != nil {
+			r.OnProgress(progress)
+		}
+		mu.Unlock()
+		results <- res
+	}
+	capReached := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return r.MaxCost > 0 && progress.Cost >= r.MaxCost
+	}
+
+	go func() {
+		var wg sync.WaitGroup
+		defer func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		for index := 0; ; index++ {
+			var req ChatCompletionRequest
+			var ok bool
+			select {
+			case req, ok = <-requests:
+			case <-ctx.Done():
+				return
+			}
+			if !ok {
+				return
+			}
+
+			// A slot is held until the item is emitted, so at most
+			// concurrency items are in flight or waiting to be emitted.
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			if capReached() {
+				finish(BatchResult{Index: index, Err: ErrSpendCapReached})
+				continue
+			}
+
+			wg.Add(1)
+			go func(index int, req ChatCompletionRequest) {
+				defer wg.Done()
+
+				if r.MaxCost > 0 {
+					req.Usage = &UsageConfig{Include: true}
+				}
+				resp, err := r.client.CreateChatCompletion(ctx, req)
+				if err != nil {
+					resp = nil
+				}
+				finish(BatchResult{Index: index, Response: resp, Err: err})
+			}(index, req)
+		}
+	}()
+
+	// Results arrive in completion order; hold them back until every
+	// earlier item has been emitted. The held slots keep pending small.
+	pending := make(map[int]BatchResult, concurrency)
+	next := 0
+	for res := range results {
+		pending[res.Index] = res
+		for {
+			res, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			emit(res)
+			<-sem
+			next++
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package openrouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// batchServer answers each request with its model as the response ID, after
+// a delay that makes later requests finish first. Model "bad" fails.
+func batchServer(t *testing.T, sent *atomic.Int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent.Add(1)
+		var req ChatCompletionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		if req.Model == "bad" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":400,"message":"bad model"}}`))
+			return
+		}
+		if n, err := strconv.Atoi(req.Model); err == nil {
+			select {
+			case <-time.After(time.Duration(10-n) * 5 * time.Millisecond):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		cost := 0.0
+		if req.Usage != nil && req.Usage.Include {
+			cost = 0.1
+		}
+		fmt.Fprintf(w, `{"id":%q,"usage":{"cost":%v}}`, req.Model, cost)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func batchRequests(models ...string) []ChatCompletionRequest {
+	reqs := make([]ChatCompletionRequest, len(models))
+	for i, m := range models {
+		reqs[i] = ChatCompletionRequest{Model: m}
+	}
+	return reqs
+}
+
+func TestBatchRunnerOrder(t *testing.T) {
+	var sent atomic.Int32
+	srv := batchServer(t, &sent)
+
+	runner := NewBatchRunner(NewClient("key", WithBaseURL(srv.URL)))
+	runner.Concurrency = 4
+	var last BatchProgress
+	runner.OnProgress = func(p BatchProgress) { last = p }
+
+	reqs := batchRequests("0", "1", "2", "bad", "4", "5", "6", "7")
+	results, err := runner.Run(context.Background(), reqs)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	for i, res := range results {
+		if res.Index != i {
+			t.Errorf("results[%d].Index = %d", i, res.Index)
+		}
+		if reqs[i].Model == "bad" {
+			if res.Err == nil {
+				t.Errorf("results[%d]: expected an error", i)
+			}
+			continue
+		}
+		if res.Err != nil || res.Response.ID != reqs[i].Model {
+			t.Errorf("results[%d] = %+v, want response %q", i, res, reqs[i].Model)
+		}
+	}
+	if last.Completed != 8 || last.Failed != 1 || last.Total != 8 {
+		t.Errorf("last progress = %+v", last)
+	}
+}
+
+func TestBatchRunnerStreamOrder(t *testing.T) {
+	var sent atomic.Int32
+	srv := batchServer(t, &sent)
+	runner := NewBatchRunner(NewClient("key", WithBaseURL(srv.URL)))
+
+	in := make(chan ChatCompletionRequest)
+	go func() {
+		defer close(in)
+		for _, req := range batchRequests("0", "1", "2", "3", "4", "5") {
+			in <- req
+		}
+	}()
+
+	next := 0
+	for res := range runner.Stream(context.Background(), in) {
+		if res.Index != next || res.Err != nil || res.Response.ID != strconv.Itoa(next) {
+			t.Errorf("result %d = %+v", next, res)
+		}
+		next++
+	}
+	if next != 6 {
+		t.Errorf("got %d results, want 6", next)
+	}
+}
+
+func TestBatchRunnerSpendCap(t *testing.T) {
+	var sent atomic.Int32
+	srv := batchServer(t, &sent)
+
+	runner := NewBatchRunner(NewClient("key", WithBaseURL(srv.URL)))
+	runner.Concurrency = 1
+	runner.MaxCost = 0.25
+
+	results, err := runner.Run(context.Background(), batchRequests("0", "1", "2", "3", "4", "5"))
+	if !errors.Is(err, ErrSpendCapReached) {
+		t.Fatalf("err = %v, want ErrSpendCapReached", err)
+	}
+	if got := sent.Load(); got != 3 {
+		t.Errorf("sent %d requests, want 3", got)
+	}
+	for i, res := range results {
+		capped := errors.Is(res.Err, ErrSpendCapReached)
+		if capped != (i >= 3) {
+			t.Errorf("results[%d].Err = %v", i, res.Err)
+		}
+	}
+}
+
+func TestBatchRunnerCancel(t *testing.T) {
+	var sent atomic.Int32
+	srv := batchServer(t, &sent)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runner := NewBatchRunner(NewClient("key", WithBaseURL(srv.URL)))
+	runner.Concurrency = 2
+	runner.OnProgress = func(p BatchProgress) {
+		if p.Completed == 2 {
+			cancel()
+		}
+	}
+
+	reqs := batchRequests("0", "1", "2", "3", "4", "5", "6", "7", "8", "9")
+	results, err := runner.Run(ctx, reqs)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if len(results) != len(reqs) {
+		t.Fatalf("got %d results, want %d", len(results), len(reqs))
+	}
+
+	failed := 0
+	for i, res := range results {
+		if res.Index != i {
+			t.Errorf("results[%d].Index = %d", i, res.Index)
+		}
+		if res.Err != nil {
+			failed++
+		}
+	}
+	if failed == 0 || int(sent.Load()) == len(reqs) {
+		t.Errorf("cancellation did not stop the batch: %d failed, %d sent", failed, sent.Load())
+	}
+}
+
+func TestBatchRunnerStalledItemBoundsWindow(t *testing.T) {
+	var sent atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent.Add(1)
+		var req ChatCompletionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Model == "0" {
+			<-release
+		}
+		fmt.Fprintf(w, `{"id":%q}`, req.Model)
+	}))
+	defer srv.Close()
+
+	runner := NewBatchRunner(NewClient("key", WithBaseURL(srv.URL)))
+	runner.Concurrency = 3
+
+	in := make(chan ChatCompletionRequest, 10)
+	for _, req := range batchRequests("0", "1", "2", "3", "4", "5", "6", "7", "8", "9") {
+		in <- req
+	}
+	close(in)
+	out := runner.Stream(context.Background(), in)
+
+	deadline := time.Now().Add(time.Second)
+	for sent.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := sent.Load(); got != 3 {
+		t.Errorf("sent %d requests while item 0 stalled, want 3", got)
+	}
+	select {
+	case res := <-out:
+		t.Errorf("delivered %+v before item 0", res)
+	default:
+	}
+
+	close(release)
+	next := 0
+	for res := range out {
+		if res.Index != next || res.Err != nil {
+			t.Errorf("result %d = %+v", next, res)
+		}
+		next++
+	}
+	if next != 10 {
+		t.Errorf("got %d results, want 10", next)
+	}
+}
@@ -0,0 +1,119 @@
+package openrouter
+
+// maxCacheBreakpoints is the number of cache_control breakpoints providers
+// such as Anthropic accept per request.
+const maxCacheBreakpoints = 4
+
+// CacheOptions configures ApplyCacheBreakpoints.
+type CacheOptions struct {
+	// Cache lifetime ("5m" or "1h"); empty uses the provider default.
+	TTL string
+
+	// Skip prefixes shorter than this many characters, since providers do not
+	// cache small prompts (Anthropic requires 1024+ tokens).
+	MinChars int
+}
+
+// EphemeralCache returns a cache_control breakpoint with an optional TTL.
+func EphemeralCache(ttl string) *CacheControl {
+	return &CacheControl{Type: "ephemeral", TTL: ttl}
+}
+
+// ApplyCacheBreakpoints returns a copy of messages with cache_control
+// breakpoints placed at the end of the system prompt and at the end of the
+// conversation prefix that precedes the latest message, which are the parts
+// that stay the same on the next call. String content is converted to a text
+// part where needed. Existing breakpoints are kept and count towards the
+// provider limit of four.
+func ApplyCacheBreakpoints(messages []ChatMessage, opts CacheOptions) []ChatMessage {
+	out := make([]ChatMessage, len(messages))
+	copy(out, messages)
+
+	used := 0
+	for _, msg := range out {
+		if parts, ok := msg.Content.([]ContentPart); ok {
+			for _, p := range parts {
+				if p.CacheControl != nil {
+					used++
+				}
+			}
+		}
+	}
+
+	// Candidates, from the most to the least valuable: the longest stable
+	// prefix first, then the system prompt.
+	var candidates []int
+	if len(out) > 1 {
+		candidates = append(candidates, len(out)-2)
+	}
+	lastSystem := -1
+	for i, msg := range out {
+		if msg.Role == "system" || msg.Role == "developer" {
+			lastSystem = i
+		}
+	}
+	if lastSystem >= 0 && (len(candidates) == 0 || candidates[0] != lastSystem) {
+		candidates = append(candidates, lastSystem)
+	}
+
+	for _, i := range candidates {
+		if used >= maxCacheBreakpoints {
+			break
+		}
+		if opts.MinChars > 0 && prefixChars(out[:i+1]) < opts.MinChars {
+			continue
+		}
+		if msg, ok := withBreakpoint(out[i], opts.TTL); ok {
+			out[i] = msg
+			used++
+		}
+	}
+
+	return out
+}
+
+// withBreakpoint marks the last text part of msg. It reports false when msg
+// has no text or is already marked there.
+func withBreakpoint(msg ChatMessage, ttl string) (ChatMessage, bool) {
+	var parts []ContentPart
+	switch c := msg.Content.(type) {
+	case string:
+		if c == "" {
+			return msg, false
+		}
+		parts = []ContentPart{TextPart(c)}
+	case []ContentPart:
+		parts = append([]ContentPart(nil), c...)
+	default:
+		return msg, false
+	}
+
+	for i := len(parts) - 1; i >= 0; i-- {
+		if parts[i].Type != "text" {
+			continue
+		}
+		if parts[i].CacheControl != nil {
+			return msg, false
+		}
+		parts[i].CacheControl = EphemeralCache(ttl)
+		msg.Content = parts
+		return msg, true
+	}
+
+	return msg, false
+}
+
+func prefixChars(messages []ChatMessage) int {
+	n := 0
+	for _, msg := range messages {
+		switch c := msg.Content.(type) {
+		case string:
+			n += len(c)
+		case []ContentPart:
+			for _, p := range c {
+				n += len(p.Text)
+			}
+		}
+	}
+	return n
+}
@@ -1,12 +1,11 @@
 package openrouter
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
 )
 
@@ -15,13 +14,18 @@ import (
 func (c *Client) CreateChatCompletion(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionResponse, error) {
 	req.Stream = false // Force stream to false for this method
 
-	httpReq, err := c.newRequest(ctx, http.MethodPost, "/chat/completions", req)
+	if err := req.Provider.Validate(); err != nil {
+		return nil, err
+	}
+
+	// The body is marshaled from the typed request after middleware has run
+	httpReq, err := c.newRequest(AllowRetry(ctx), http.MethodPost, "/chat/completions", nil)
 	if err != nil {
 		return nil, err
 	}
 
 	var resp ChatCompletionResponse
-	if err := c.sendRequest(httpReq, &resp); err != nil {
+	if err := c.sendCall(&Call{Request: &req, HTTPRequest: httpReq}, &resp); err != nil {
 		return nil, err
 	}
 
@@ -34,45 +38,54 @@ func (c *Client) CreateChatCompletion(ctx context.Context, req ChatCompletionReq
 
 // ChatCompletionStream manages the stream of responses.
 type ChatCompletionStream struct {
-	reader *bufio.Reader
+	events *sseReader
 	body   io.Closer
+
+	// Called once with the usage reported on the stream, if any.
+	onUsage func(*Usage)
 }
 
-// Recv returns the next response from the stream.
-// Returns io.EOF when the stream is finished.
+// RecvEvent returns the next raw Server-Sent Event from the stream, including
+// heartbeat comments. Most callers should use Recv instead.
+func (s *ChatCompletionStream) RecvEvent() (*StreamEvent, error) {
+	return s.events.next()
+}
+
+// Recv returns the next response from the stream, skipping heartbeats.
+// Returns io.EOF when the stream is finished, or a *StreamError when the
+// provider fails mid-stream.
 func (s *ChatCompletionStream) Recv() (*ChatCompletionResponse, error) {
 	for {
-		// Read line by line (SSE format)
-		line, err := s.reader.ReadBytes('\n')
+		event, err := s.events.next()
 		if err != nil {
 			return nil, err
 		}
 
-		line = bytes.TrimSpace(line)
-		if len(line) == 0 {
-			continue
-		}
-
-		// SSE lines start with "data: "
-		if !bytes.HasPrefix(line, []byte("data: ")) {
+		// Keep-alive comments and events without a payload carry no response
+		if event.Heartbeat || event.Data == "" {
 			continue
 		}
 
-		// Remove the prefix
-		data := bytes.TrimPrefix(line, []byte("data: "))
-
 		// Check for the [DONE] signal
-		if string(data) == "[DONE]" {
+		if event.Data == "[DONE]" {
 			return nil, io.EOF
 		}
 
 		var response ChatCompletionResponse
-		if err := json.Unmarshal(data, &response); err != nil {
+		if err := json.Unmarshal([]byte(event.Data), &response); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal stream data: %w", err)
 		}
 
-		// OpenRouter (and OpenAI) sometimes send keep-alive comments or empty updates
-		// We return valid structs, but empty ones might be filtered by the caller if desired.
+		// The provider failed after streaming started
+		if response.Error != nil {
+			return nil, newStreamError(&response)
+		}
+
+		if response.Usage != nil && s.onUsage != nil {
+			s.onUsage(response.Usage)
+			s.onUsage = nil
+		}
+
 		return &response, nil
 	}
 }
@@ -82,32 +95,79 @@ func (s *ChatCompletionStream) Close() error {
 	return s.body.Close()
 }
 
+// All returns an iterator over the remaining responses in the stream.
+// Iteration stops after io.EOF or the first error, which is yielded.
+// The stream is closed when iteration ends, including on an early break.
+func (s *ChatCompletionStream) All() iter.Seq2[*ChatCompletionResponse, error] {
+	return func(yield func(*ChatCompletionResponse, error) bool) {
+		defer s.Close()
+		for {
+			resp, err := s.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(resp, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Text returns an iterator over the non-empty text deltas of the first choice.
+// It closes the stream the same way All does.
+func (s *ChatCompletionStream) Text() iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for resp, err := range s.All() {
+			if err != nil {
+				yield("", err)
+				return
+			}
+			if len(resp.Choices) == 0 || resp.Choices[0].Delta == nil {
+				continue
+			}
+			text, ok := resp.Choices[0].Delta.Content.(string)
+			if !ok || text == "" {
+				continue
+			}
+			if !yield(text, nil) {
+				return
+			}
+		}
+	}
+}
+
 // CreateChatCompletionStream sends a request to the chat completions endpoint with streaming enabled.
 func (c *Client) CreateChatCompletionStream(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionStream, error) {
 	req.Stream = true // Force stream to true
 
-	httpReq, err := c.newRequest(ctx, http.MethodPost, "/chat/completions", req)
-	if err != nil {
+	if err := req.Provider.Validate(); err != nil {
 		return nil, err
 	}
 
-	// We use c.httpClient.Do directly here because we need to keep the body open
-	resp, err := c.httpClient.Do(httpReq)
+	httpReq, err := c.newRequest(AllowRetry(ctx), http.MethodPost, "/chat/completions", nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute stream request: %w", err)
+		return nil, err
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		defer resp.Body.Close()
-		var errResp ErrorResponse
-		if decodeErr := json.NewDecoder(resp.Body).Decode(&errResp); decodeErr == nil && errResp.Error.Message != "" {
-			return nil, fmt.Errorf("api error (status %d): %s - %s", resp.StatusCode, errResp.Error.Type, errResp.Error.Message)
-		}
-		return nil, fmt.Errorf("api error (status %d)", resp.StatusCode)
+	// Retries only cover the initial connect; the body is kept open for Recv
+	result, err := c.execute(&Call{Request: &req, HTTPRequest: httpReq, Streaming: true}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if result.Stream == nil {
+		return nil, fmt.Errorf("middleware returned no stream")
 	}
 
+	return result.Stream, nil
+}
+
+func newChatCompletionStream(body io.ReadCloser) *ChatCompletionStream {
 	return &ChatCompletionStream{
-		reader: bufio.NewReader(resp.Body),
-		body:   resp.Body,
-	}, nil
+		events: newSSEReader(body),
+		body:   body,
+	}
 }
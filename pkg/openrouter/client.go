@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 )
 
@@ -20,10 +21,16 @@ type Client struct {
 	apiKey     string
 	baseURL    string
 	httpClient *http.Client
+	retry      RetryPolicy
+	middleware []Middleware
+	limiter    *rateLimiter
 
 	// OpenRouter specific headers for app rankings
 	httpReferer string // Optional: URL of your site
 	xTitle      string // Optional: Name of your site
+
+	// Keys manages API keys (requires a provisioning key).
+	Keys *KeysService
 }
 
 // Option defines a functional option for configuring the Client.
@@ -69,6 +76,8 @@ func NewClient(apiKey string, opts ...Option) *Client {
 		opt(c)
 	}
 
+	c.Keys = &KeysService{client: c}
+
 	return c
 }
 
@@ -91,6 +100,23 @@ func (c *Client) ListModels(ctx context.Context) (*ListModelsResponse, error) {
 	return &resp, nil
 }
 
+// ListModelEndpoints retrieves every provider endpoint serving the model
+// identified by author and slug (e.g. "openai", "gpt-4o").
+func (c *Client) ListModelEndpoints(ctx context.Context, author, slug string) (*ModelEndpoints, error) {
+	path := fmt.Sprintf("/models/%s/%s/endpoints", url.PathEscape(author), url.PathEscape(slug))
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ListModelEndpointsResponse
+	if err := c.sendRequest(req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Data, nil
+}
+
 // -----------------------------------------------------------------------------
 // Internal Helpers
 // -----------------------------------------------------------------------------
@@ -125,30 +151,14 @@ func (c *Client) newRequest(ctx context.Context, method, path string, payload in
 }
 
 func (c *Client) sendRequest(req *http.Request, v interface{}) error {
-	res, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer res.Body.Close()
-
-	// Check for non-2xx status codes
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		var errResp ErrorResponse
-		// Try to decode the error response
-		if decodeErr := json.NewDecoder(res.Body).Decode(&errResp); decodeErr == nil && errResp.Error.Message != "" {
-			return fmt.Errorf("api error (status %d): %s - %s", res.StatusCode, errResp.Error.Type, errResp.Error.Message)
-		}
-		// Fallback if JSON decoding fails
-		return fmt.Errorf("api error (status %d)", res.StatusCode)
-	}
-
-	if v == nil {
-		return nil
-	}
+	return c.sendCall(&Call{HTTPRequest: req}, v)
+}
 
-	if err := json.NewDecoder(res.Body).Decode(v); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
+// sendCall runs call through the middleware chain and decodes the response into v.
+func (c *Client) sendCall(call *Call, v interface{}) error {
+	result, err := c.execute(call, v)
+	if err != nil {
+		return err
 	}
-
-	return nil
+	return copyResult(result, v)
 }
@@ -0,0 +1,267 @@
+package openrouter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIError is returned for any non-2xx response from the OpenRouter API.
+// Use errors.As to inspect it, or the Is* helpers for common cases.
+type APIError struct {
+	// HTTP status code of the response.
+	StatusCode int
+
+	// Error code reported by OpenRouter or the upstream provider, stringified
+	// (e.g. "429", "context_length_exceeded").
+	Code string
+
+	// Error type, when the provider reports one (e.g. "invalid_request_error").
+	Type string
+
+	// Human-readable error message.
+	Message string
+
+	// Parameter that caused the error, if reported.
+	Param interface{}
+
+	// Raw error metadata as returned by OpenRouter.
+	Metadata map[string]interface{}
+
+	// Name of the upstream provider that produced the error, if any.
+	ProviderName string
+
+	// Raw error payload from the upstream provider, if any.
+	RawProviderError string
+
+	// Reasons reported when the input was flagged by moderation.
+	ModerationReasons []string
+
+	// The input that was flagged by moderation, if any.
+	FlaggedInput string
+
+	// Request ID taken from the response headers, if present.
+	RequestID string
+
+	// Delay requested by the server via the Retry-After header.
+	RetryAfter time.Duration
+
+	// Response headers.
+	Header http.Header
+
+	// Raw response body, kept when it could not be decoded as an ErrorResponse.
+	Body string
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "api error (status %d)", e.StatusCode)
+	if e.Type != "" {
+		fmt.Fprintf(&b, ": %s", e.Type)
+		if e.Message != "" {
+			fmt.Fprintf(&b, " - %s", e.Message)
+		}
+	} else if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	if e.ProviderName != "" {
+		fmt.Fprintf(&b, " (provider: %s)", e.ProviderName)
+	}
+	return b.String()
+}
+
+// StreamError is returned by ChatCompletionStream.Recv when OpenRouter sends an
+// error chunk after streaming has started. It unwraps to an *APIError, so the
+// Is* helpers work on it too.
+type StreamError struct {
+	// The error reported in the chunk.
+	Err *APIError
+
+	// The chunk that carried the error.
+	Response *ChatCompletionResponse
+}
+
+// Error implements the error interface.
+func (e *StreamError) Error() string {
+	return "stream error: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying APIError.
+func (e *StreamError) Unwrap() error {
+	return e.Err
+}
+
+// newStreamError builds a StreamError from a chunk carrying an error object.
+func newStreamError(chunk *ChatCompletionResponse) *StreamError {
+	apiErr := &APIError{}
+	apiErr.applyDetails(*chunk.Error)
+	if code, err := strconv.Atoi(apiErr.Code); err == nil {
+		apiErr.StatusCode = code
+	}
+	return &StreamError{Err: apiErr, Response: chunk}
+}
+
+// IsRateLimited reports whether err is an APIError caused by rate limiting.
+func IsRateLimited(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.Code == "rate_limit_exceeded"
+}
+
+// IsInsufficientCredits reports whether err is an APIError caused by an
+// exhausted credit balance.
+func IsInsufficientCredits(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusPaymentRequired || apiErr.Code == "insufficient_quota"
+}
+
+// IsContextLengthExceeded reports whether err is an APIError caused by a
+// prompt that does not fit the model's context window.
+func IsContextLengthExceeded(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.Code == "context_length_exceeded" {
+		return true
+	}
+	msg := strings.ToLower(apiErr.Message + " " + apiErr.RawProviderError)
+	return strings.Contains(msg, "context length") ||
+		strings.Contains(msg, "context window") ||
+		strings.Contains(msg, "maximum context")
+}
+
+// IsModerated reports whether err is an APIError caused by the input being
+// flagged by moderation.
+func IsModerated(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusForbidden && (len(apiErr.ModerationReasons) > 0 || apiErr.FlaggedInput != "")
+}
+
+// IsNotFound reports whether err is an APIError with a 404 status.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusNotFound
+}
+
+// -----------------------------------------------------------------------------
+// Internal Helpers
+// -----------------------------------------------------------------------------
+
+// maxErrorBodySize bounds how much of an error response body is read.
+const maxErrorBodySize = 1 << 20
+
+// newAPIError builds an APIError from a non-2xx response. The caller remains
+// responsible for closing the response body.
+func newAPIError(res *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+		RequestID:  requestID(res.Header),
+		RetryAfter: parseRetryAfter(res.Header.Get("Retry-After")),
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err != nil || errResp.Error.Message == "" {
+		apiErr.Body = string(body)
+		apiErr.Message = http.StatusText(res.StatusCode)
+		return apiErr
+	}
+
+	apiErr.applyDetails(errResp.Error)
+	return apiErr
+}
+
+// applyDetails copies the decoded error payload into e.
+func (e *APIError) applyDetails(d ErrorDetails) {
+	e.Code = codeString(d.Code)
+	e.Type = d.Type
+	e.Message = d.Message
+	e.Param = d.Param
+	e.Metadata = d.Metadata
+
+	if name, ok := d.Metadata["provider_name"].(string); ok {
+		e.ProviderName = name
+	}
+	switch raw := d.Metadata["raw"].(type) {
+	case string:
+		e.RawProviderError = raw
+	case nil:
+	default:
+		if b, err := json.Marshal(raw); err == nil {
+			e.RawProviderError = string(b)
+		}
+	}
+	if reasons, ok := d.Metadata["reasons"].([]interface{}); ok {
+		for _, r := range reasons {
+			if s, ok := r.(string); ok {
+				e.ModerationReasons = append(e.ModerationReasons, s)
+			}
+		}
+	}
+	if flagged, ok := d.Metadata["flagged_input"].(string); ok {
+		e.FlaggedInput = flagged
+	}
+}
+
+// codeString normalizes an error code, which may be a number or a string.
+func codeString(code interface{}) string {
+	switch v := code.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func requestID(h http.Header) string {
+	for _, key := range []string{"X-Request-Id", "X-Generation-Id", "Cf-Ray"} {
+		if v := h.Get(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(v string) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.ParseFloat(v, 64); err == nil {
+		if secs <= 0 {
+			return 0
+		}
+		return time.Duration(secs * float64(time.Second))
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
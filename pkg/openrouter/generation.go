@@ -0,0 +1,56 @@
+package openrouter
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// defaultGenerationPollInterval is used by WaitForGeneration when no interval is given.
+const defaultGenerationPollInterval = 500 * time.Millisecond
+
+// GetGeneration retrieves the stats for a generation by its ID,
+// i.e. the ChatCompletionResponse.ID of a completed request.
+// Stats are usually available shortly after the request finishes; until then
+// the API responds with 404 (see IsNotFound).
+func (c *Client) GetGeneration(ctx context.Context, id string) (*GenerationStats, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/generation?id="+url.QueryEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp GenerationResponse
+	if err := c.sendRequest(req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Data, nil
+}
+
+// WaitForGeneration polls GetGeneration every interval until the stats become
+// available, a non-404 error occurs, or ctx is done.
+func (c *Client) WaitForGeneration(ctx context.Context, id string, interval time.Duration) (*GenerationStats, error) {
+	if interval <= 0 {
+		interval = defaultGenerationPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		stats, err := c.GetGeneration(ctx, id)
+		if err == nil {
+			return stats, nil
+		}
+		if !IsNotFound(err) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package openrouter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// KeysService manages API keys through OpenRouter's provisioning API.
+// Its endpoints require the Client to be configured with a provisioning key.
+type KeysService struct {
+	client *Client
+}
+
+// List returns the API keys of the account.
+func (s *KeysService) List(ctx context.Context, opts *ListKeysOptions) ([]APIKey, error) {
+	query := url.Values{}
+	if opts != nil {
+		if opts.Offset > 0 {
+			query.Set("offset", strconv.Itoa(opts.Offset))
+		}
+		if opts.IncludeDisabled {
+			query.Set("include_disabled", "true")
+		}
+	}
+
+	path := "/keys"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	req, err := s.client.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ListKeysResponse
+	if err := s.client.sendRequest(req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
+// Create creates a new API key. The secret is only available in the returned
+// CreateKeyResponse.Key.
+func (s *KeysService) Create(ctx context.Context, body CreateKeyRequest) (*CreateKeyResponse, error) {
+	if body.Name == "" {
+		return nil, fmt.Errorf("key name is required")
+	}
+
+	req, err := s.client.newRequest(ctx, http.MethodPost, "/keys", body)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CreateKeyResponse
+	if err := s.client.sendRequest(req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Get retrieves a single API key by its hash.
+func (s *KeysService) Get(ctx context.Context, hash string) (*APIKey, error) {
+	req, err := s.client.newRequest(ctx, http.MethodGet, keyPath(hash), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp APIKeyResponse
+	if err := s.client.sendRequest(req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Data, nil
+}
+
+// Update changes the name, limit or disabled state of an API key.
+func (s *KeysService) Update(ctx context.Context, hash string, body UpdateKeyRequest) (*APIKey, error) {
+	// The update sets absolute values, so sending it twice is harmless.
+	req, err := s.client.newRequest(AllowRetry(ctx), http.MethodPatch, keyPath(hash), body)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp APIKeyResponse
+	if err := s.client.sendRequest(req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Data, nil
+}
+
+// Disable is a shorthand for Update setting Disabled to true.
+func (s *KeysService) Disable(ctx context.Context, hash string) (*APIKey, error) {
+	disabled := true
+	return s.Update(ctx, hash, UpdateKeyRequest{Disabled: &disabled})
+}
+
+// Delete permanently deletes an API key.
+func (s *KeysService) Delete(ctx context.Context, hash string) error {
+	req, err := s.client.newRequest(ctx, http.MethodDelete, keyPath(hash), nil)
+	if err != nil {
+		return err
+	}
+
+	var resp DeleteKeyResponse
+	if err := s.client.sendRequest(req, &resp); err != nil {
+		return err
+	}
+	if !resp.Deleted {
+		return fmt.Errorf("key %s was not deleted", hash)
+	}
+
+	return nil
+}
+
+func keyPath(hash string) string {
+	return "/keys/" + url.PathEscape(hash)
+}
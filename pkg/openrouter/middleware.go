@@ -0,0 +1,131 @@
+package openrouter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+// Call describes an API call passing through the middleware chain.
+type Call struct {
+	// The typed chat completion request; nil for other endpoints.
+	// Changes made by middleware are marshaled into the request body.
+	Request *ChatCompletionRequest
+
+	// The HTTP request to be sent. Middleware may add headers or replace it.
+	HTTPRequest *http.Request
+
+	// Whether the call opens a stream.
+	Streaming bool
+}
+
+// Result is the outcome of a Call.
+type Result struct {
+	// The decoded response body, e.g. *ChatCompletionResponse or
+	// *ListModelsResponse. Nil for streaming calls.
+	Value interface{}
+
+	// The open stream, for streaming calls.
+	Stream *ChatCompletionStream
+
+	// The HTTP response. Its body has already been consumed, or is owned by Stream.
+	HTTPResponse *http.Response
+}
+
+// Handler performs a Call.
+type Handler func(ctx context.Context, call *Call) (*Result, error)
+
+// Middleware wraps a Handler to add behavior around every API call.
+type Middleware func(next Handler) Handler
+
+// WithMiddleware appends middleware to the client. The first middleware given
+// is the outermost one.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Internal Helpers
+// -----------------------------------------------------------------------------
+
+// execute runs call through the middleware chain. v is the value the
+// response body is decoded into for non-streaming calls.
+func (c *Client) execute(call *Call, v interface{}) (*Result, error) {
+	h := c.transport(v)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
+	}
+	return h(call.HTTPRequest.Context(), call)
+}
+
+// transport returns the innermost Handler, which sends the HTTP request.
+func (c *Client) transport(v interface{}) Handler {
+	return func(ctx context.Context, call *Call) (*Result, error) {
+		req := call.HTTPRequest
+		if req.Context() != ctx {
+			req = req.WithContext(ctx)
+		}
+
+		if call.Request != nil {
+			b, err := json.Marshal(call.Request)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal request body: %w", err)
+			}
+			req.Body = io.NopCloser(bytes.NewReader(b))
+			req.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(b)), nil
+			}
+			req.ContentLength = int64(len(b))
+		}
+
+		var limited *limitedCall
+		if c.limiter != nil {
+			limited = c.limiter.prepare(ctx, c, call)
+		}
+
+		res, err := c.do(req, limited)
+		if err != nil {
+			return nil, err
+		}
+
+		if call.Streaming {
+			stream := newChatCompletionStream(res.Body)
+			if limited != nil {
+				stream.onUsage = limited.settle
+			}
+			return &Result{Stream: stream, HTTPResponse: res}, nil
+		}
+		defer res.Body.Close()
+
+		if v != nil {
+			if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+				return nil, fmt.Errorf("failed to decode response: %w", err)
+			}
+		}
+		if resp, ok := v.(*ChatCompletionResponse); ok && limited != nil {
+			limited.settle(resp.Usage)
+		}
+
+		return &Result{Value: v, HTTPResponse: res}, nil
+	}
+}
+
+// copyResult stores result.Value into v when middleware replaced the value
+// with another one of the same type (e.g. a cached response).
+func copyResult(result *Result, v interface{}) error {
+	if v == nil || result == nil || result.Value == nil || result.Value == v {
+		return nil
+	}
+	dst, src := reflect.ValueOf(v), reflect.ValueOf(result.Value)
+	if dst.Type() != src.Type() || dst.Kind() != reflect.Pointer || src.IsNil() {
+		return fmt.Errorf("middleware returned %T, want %T", result.Value, v)
+	}
+	dst.Elem().Set(src.Elem())
+	return nil
+}
@@ -1,6 +1,11 @@
 package openrouter
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+)
 
 // -----------------------------------------------------------------------------
 // Models API (GET /models)
@@ -16,9 +21,15 @@ type Model struct {
 	// The unique identifier for the model (e.g., "anthropic/claude-3-opus").
 	ID string `json:"id"`
 
+	// The permanent slug of the model, which does not change across renames.
+	CanonicalSlug string `json:"canonical_slug,omitempty"`
+
 	// The human-readable name of the model.
 	Name string `json:"name"`
 
+	// Unix timestamp of when the model was added to OpenRouter.
+	Created int64 `json:"created,omitempty"`
+
 	// A description of the model's capabilities.
 	Description string `json:"description"`
 
@@ -33,6 +44,48 @@ type Model struct {
 
 	// The primary provider for this model in OpenRouter's routing.
 	TopProvider ProviderInfo `json:"top_provider"`
+
+	// Per-request token limits, if any.
+	PerRequestLimits *PerRequestLimits `json:"per_request_limits,omitempty"`
+
+	// Request parameters supported by at least one provider (e.g. "tools").
+	SupportedParameters []string `json:"supported_parameters,omitempty"`
+}
+
+// SupportsParameter reports whether the model accepts the given request parameter.
+func (m Model) SupportsParameter(name string) bool {
+	return slices.Contains(m.SupportedParameters, name)
+}
+
+// SupportsTools reports whether the model supports tool calling.
+func (m Model) SupportsTools() bool {
+	return m.SupportsParameter("tools")
+}
+
+// SupportsStructuredOutputs reports whether the model supports json_schema
+// response formats.
+func (m Model) SupportsStructuredOutputs() bool {
+	return m.SupportsParameter("structured_outputs")
+}
+
+// SupportsReasoning reports whether the model can return reasoning tokens.
+func (m Model) SupportsReasoning() bool {
+	return m.SupportsParameter("reasoning") || m.SupportsParameter("include_reasoning")
+}
+
+// SupportsImages reports whether the model accepts image input.
+func (m Model) SupportsImages() bool {
+	return m.Architecture.acceptsInput("image")
+}
+
+// SupportsFiles reports whether the model accepts file (e.g. PDF) input.
+func (m Model) SupportsFiles() bool {
+	return m.Architecture.acceptsInput("file")
+}
+
+// SupportsAudio reports whether the model accepts audio input.
+func (m Model) SupportsAudio() bool {
+	return m.Architecture.acceptsInput("audio")
 }
 
 // ModelArchitecture describes the technical details of the model.
@@ -45,6 +98,22 @@ type ModelArchitecture struct {
 
 	// The modality of the model (e.g., "text->text", "text+image->text").
 	Modality string `json:"modality"`
+
+	// Accepted input modalities (e.g., "text", "image", "file", "audio").
+	InputModalities []string `json:"input_modalities,omitempty"`
+
+	// Produced output modalities (e.g., "text", "image").
+	OutputModalities []string `json:"output_modalities,omitempty"`
+}
+
+// acceptsInput reports whether the given input modality is supported, falling
+// back to the Modality string for older responses.
+func (a ModelArchitecture) acceptsInput(modality string) bool {
+	if len(a.InputModalities) > 0 {
+		return slices.Contains(a.InputModalities, modality)
+	}
+	input, _, _ := strings.Cut(a.Modality, "->")
+	return slices.Contains(strings.Split(input, "+"), modality)
 }
 
 // ModelPricing defines the cost structure for the model.
@@ -61,11 +130,100 @@ type ModelPricing struct {
 
 	// Cost per request (if applicable).
 	Request string `json:"request"`
+
+	// Cost per web search (if applicable).
+	WebSearch string `json:"web_search,omitempty"`
+
+	// Cost per internal reasoning token (if applicable).
+	InternalReasoning string `json:"internal_reasoning,omitempty"`
+
+	// Cost per input token read from the prompt cache (if applicable).
+	InputCacheRead string `json:"input_cache_read,omitempty"`
+
+	// Cost per input token written to the prompt cache (if applicable).
+	InputCacheWrite string `json:"input_cache_write,omitempty"`
 }
 
 // ProviderInfo contains details about the model provider.
 type ProviderInfo struct {
 	Name string `json:"name"`
+
+	// The maximum context length (tokens) offered by the provider.
+	ContextLength *int `json:"context_length,omitempty"`
+
+	// The maximum number of completion tokens the provider will generate.
+	MaxCompletionTokens *int `json:"max_completion_tokens,omitempty"`
+
+	// Whether the provider moderates requests.
+	IsModerated bool `json:"is_moderated"`
+}
+
+// PerRequestLimits caps the tokens a single request may use.
+// Values may be reported as numbers or numeric strings.
+type PerRequestLimits struct {
+	PromptTokens     json.Number `json:"prompt_tokens,omitempty"`
+	CompletionTokens json.Number `json:"completion_tokens,omitempty"`
+}
+
+// -----------------------------------------------------------------------------
+// Model Endpoints API (GET /models/{author}/{slug}/endpoints)
+// -----------------------------------------------------------------------------
+
+// ListModelEndpointsResponse represents the response from the model endpoints endpoint.
+type ListModelEndpointsResponse struct {
+	Data ModelEndpoints `json:"data"`
+}
+
+// ModelEndpoints lists every provider endpoint serving a model.
+type ModelEndpoints struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Created      int64             `json:"created"`
+	Description  string            `json:"description"`
+	Architecture ModelArchitecture `json:"architecture"`
+	Endpoints    []ModelEndpoint   `json:"endpoints"`
+}
+
+// ModelEndpoint describes a single provider's deployment of a model.
+type ModelEndpoint struct {
+	// Display name of the endpoint (e.g. "OpenAI | openai/gpt-4o").
+	Name string `json:"name"`
+
+	// The provider name, usable in ProviderPreferences.Order.
+	ProviderName string `json:"provider_name"`
+
+	// Provider tag identifying the endpoint (e.g. "deepinfra/fp8").
+	Tag string `json:"tag,omitempty"`
+
+	// The maximum context length (tokens) supported by this endpoint.
+	ContextLength int `json:"context_length"`
+
+	// Pricing of this endpoint.
+	Pricing ModelPricing `json:"pricing"`
+
+	// Weight quantization (e.g. "fp8", "bf16"), if known.
+	Quantization string `json:"quantization,omitempty"`
+
+	// Token limits of this endpoint, if any.
+	MaxCompletionTokens *int `json:"max_completion_tokens,omitempty"`
+	MaxPromptTokens     *int `json:"max_prompt_tokens,omitempty"`
+
+	// Request parameters supported by this endpoint.
+	SupportedParameters []string `json:"supported_parameters,omitempty"`
+
+	// Endpoint health status (0 is healthy, negative values are degraded).
+	Status int `json:"status"`
+
+	// Percentage of successful requests over the last 30 minutes, if known.
+	UptimeLast30m *float64 `json:"uptime_last_30m,omitempty"`
+
+	// Whether the endpoint caches prompts without explicit cache_control.
+	SupportsImplicitCaching bool `json:"supports_implicit_caching,omitempty"`
+}
+
+// SupportsParameter reports whether the endpoint accepts the given request parameter.
+func (e ModelEndpoint) SupportsParameter(name string) bool {
+	return slices.Contains(e.SupportedParameters, name)
 }
 
 // -----------------------------------------------------------------------------
@@ -93,6 +251,7 @@ type ChatCompletionRequest struct {
 	Stop              []string        `json:"stop,omitempty"`
 	Tools             []Tool          `json:"tools,omitempty"`
 	ToolChoice        interface{}     `json:"tool_choice,omitempty"` // "none", "auto", or specific tool struct
+	ParallelToolCalls *bool           `json:"parallel_tool_calls,omitempty"`
 	ResponseFormat    *ResponseFormat `json:"response_format,omitempty"`
 
 	// OpenRouter Specific Parameters
@@ -108,6 +267,76 @@ type ChatCompletionRequest struct {
 
 	// List of transforms to apply (e.g., ["middle-out"]).
 	Transforms []string `json:"transforms,omitempty"`
+
+	// Reasoning token controls for models that support them.
+	Reasoning *ReasoningConfig `json:"reasoning,omitempty"`
+
+	// Plugins to run with the request (web search, file parsing, ...).
+	Plugins []Plugin `json:"plugins,omitempty"`
+
+	// Options for models with built-in web search.
+	WebSearchOptions *WebSearchOptions `json:"web_search_options,omitempty"`
+
+	// Request detailed usage accounting (cost, cached and reasoning tokens).
+	Usage *UsageConfig `json:"usage,omitempty"`
+
+	// Streaming options; IncludeUsage adds a final chunk carrying usage.
+	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
+}
+
+// UsageConfig controls usage accounting in responses.
+type UsageConfig struct {
+	Include bool `json:"include"`
+}
+
+// StreamOptions configures streaming responses.
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// Plugin enables an OpenRouter plugin. Use the constructors in plugins.go.
+type Plugin struct {
+	// Plugin ID: "web", "file-parser" or "response-healing".
+	ID string `json:"id"`
+
+	// Web search: search engine ("native" or "exa"), empty for the default.
+	Engine string `json:"engine,omitempty"`
+
+	// Web search: maximum number of results (defaults to 5).
+	MaxResults int `json:"max_results,omitempty"`
+
+	// Web search: prompt used to attach the results to the conversation.
+	SearchPrompt string `json:"search_prompt,omitempty"`
+
+	// File parser: PDF processing options.
+	PDF *PDFOptions `json:"pdf,omitempty"`
+}
+
+// PDFOptions selects how the file-parser plugin processes PDFs.
+type PDFOptions struct {
+	// "pdf-text", "mistral-ocr" or "native".
+	Engine string `json:"engine"`
+}
+
+// WebSearchOptions configures built-in web search.
+type WebSearchOptions struct {
+	// "low", "medium" or "high".
+	SearchContextSize string `json:"search_context_size,omitempty"`
+}
+
+// ReasoningConfig controls reasoning tokens. Set either Effort or MaxTokens.
+type ReasoningConfig struct {
+	// Reasoning effort: "high", "medium", "low" or "minimal".
+	Effort string `json:"effort,omitempty"`
+
+	// Maximum number of reasoning tokens.
+	MaxTokens int `json:"max_tokens,omitempty"`
+
+	// Use reasoning internally but leave it out of the response.
+	Exclude bool `json:"exclude,omitempty"`
+
+	// Enable reasoning with default settings (implied by Effort or MaxTokens).
+	Enabled *bool `json:"enabled,omitempty"`
 }
 
 // ChatMessage represents a single message in the conversation history.
@@ -117,13 +346,69 @@ type ChatMessage struct {
 	Name       string      `json:"name,omitempty"`
 	ToolCalls  []ToolCall  `json:"tool_calls,omitempty"`
 	ToolCallID string      `json:"tool_call_id,omitempty"` // For role: tool
+
+	// Reasoning text returned by reasoning models.
+	Reasoning string `json:"reasoning,omitempty"`
+
+	// Structured reasoning blocks. Pass them back unchanged on assistant
+	// messages to preserve reasoning across tool-calling turns.
+	ReasoningDetails []ReasoningDetail `json:"reasoning_details,omitempty"`
+
+	// Annotations such as web search citations. File annotations can be sent
+	// back to skip re-parsing the same PDF.
+	Annotations []Annotation `json:"annotations,omitempty"`
 }
 
-// ContentPart represents a part of a multimodal message (text or image).
+// Annotation is a piece of metadata attached to an assistant message.
+type Annotation struct {
+	// "url_citation" or "file".
+	Type string `json:"type"`
+
+	URLCitation *URLCitation    `json:"url_citation,omitempty"`
+	File        json.RawMessage `json:"file,omitempty"` // Parsed file content, passed back as-is
+}
+
+// URLCitation is a source referenced by a web search result.
+type URLCitation struct {
+	URL        string `json:"url"`
+	Title      string `json:"title,omitempty"`
+	Content    string `json:"content,omitempty"`
+	StartIndex int    `json:"start_index"`
+	EndIndex   int    `json:"end_index"`
+}
+
+// ReasoningDetail is a single block of structured reasoning output.
+type ReasoningDetail struct {
+	// "reasoning.text", "reasoning.summary" or "reasoning.encrypted".
+	Type string `json:"type"`
+
+	Text      string `json:"text,omitempty"`      // For reasoning.text
+	Summary   string `json:"summary,omitempty"`   // For reasoning.summary
+	Data      string `json:"data,omitempty"`      // For reasoning.encrypted
+	Signature string `json:"signature,omitempty"` // Provider signature for reasoning.text
+
+	ID     string `json:"id,omitempty"`
+	Format string `json:"format,omitempty"` // Provider format (e.g. "anthropic-claude-v1")
+	Index  *int   `json:"index,omitempty"`  // Position of the block; set in stream deltas
+}
+
+// ContentPart represents a part of a multimodal message
+// ("text", "image_url", "file" or "input_audio").
 type ContentPart struct {
-	Type     string    `json:"type"`
-	Text     string    `json:"text,omitempty"`
-	ImageURL *ImageURL `json:"image_url,omitempty"`
+	Type       string      `json:"type"`
+	Text       string      `json:"text,omitempty"`
+	ImageURL   *ImageURL   `json:"image_url,omitempty"`
+	File       *FileData   `json:"file,omitempty"`
+	InputAudio *InputAudio `json:"input_audio,omitempty"`
+
+	// Marks the end of a cacheable prompt prefix (prompt caching breakpoint).
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
+}
+
+// CacheControl marks a prompt caching breakpoint.
+type CacheControl struct {
+	Type string `json:"type"`          // Always "ephemeral"
+	TTL  string `json:"ttl,omitempty"` // "5m" (default) or "1h"
 }
 
 type ImageURL struct {
@@ -131,6 +416,18 @@ type ImageURL struct {
 	Detail string `json:"detail,omitempty"` // "auto", "low", "high"
 }
 
+// FileData carries a document such as a PDF.
+type FileData struct {
+	Filename string `json:"filename,omitempty"`
+	FileData string `json:"file_data"` // Data URL or public URL
+}
+
+// InputAudio carries base64-encoded audio.
+type InputAudio struct {
+	Data   string `json:"data"`
+	Format string `json:"format"` // e.g. "wav", "mp3"
+}
+
 // ProviderPreferences defines how OpenRouter should select providers.
 type ProviderPreferences struct {
 	// Whether to allow OpenAI to run this request if the primary provider fails.
@@ -142,13 +439,53 @@ type ProviderPreferences struct {
 	// Filter providers by data collection policy.
 	DataCollection string `json:"data_collection,omitempty"` // "deny" or "allow"
 
-	// Require providers to support specific parameters.
-	RequireParameters []string `json:"require_parameters,omitempty"`
+	// Only use providers that support every parameter in the request.
+	RequireParameters *bool `json:"require_parameters,omitempty"`
+
+	// Only use providers with a zero data retention policy.
+	ZDR *bool `json:"zdr,omitempty"`
+
+	// Only use providers that allow their outputs to be used for distillation.
+	EnforceDistillableText *bool `json:"enforce_distillable_text,omitempty"`
+
+	// Restrict routing to these providers.
+	Only []string `json:"only,omitempty"`
+
+	// Never route to these providers.
+	Ignore []string `json:"ignore,omitempty"`
+
+	// Restrict routing to these quantization levels (e.g. "fp8", "bf16").
+	Quantizations []string `json:"quantizations,omitempty"`
+
+	// Sort providers by "price", "throughput" or "latency" instead of load balancing.
+	Sort string `json:"sort,omitempty"`
+
+	// Skip providers priced above these limits.
+	MaxPrice *MaxPrice `json:"max_price,omitempty"`
+}
+
+// MaxPrice caps the price of providers considered for a request, in USD per
+// million tokens for Prompt and Completion, and per unit for Image and Request.
+type MaxPrice struct {
+	Prompt     *float64 `json:"prompt,omitempty"`
+	Completion *float64 `json:"completion,omitempty"`
+	Image      *float64 `json:"image,omitempty"`
+	Request    *float64 `json:"request,omitempty"`
 }
 
 // ResponseFormat specifies the output format (e.g., JSON mode).
 type ResponseFormat struct {
-	Type string `json:"type"` // e.g., "json_object"
+	Type       string            `json:"type"`                  // "text", "json_object" or "json_schema"
+	JSONSchema *JSONSchemaFormat `json:"json_schema,omitempty"` // Required for "json_schema"
+}
+
+// JSONSchemaFormat describes the schema the response must follow in
+// json_schema mode.
+type JSONSchemaFormat struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Strict      bool            `json:"strict,omitempty"`
+	Schema      json.RawMessage `json:"schema"`
 }
 
 // Tool represents a function or capability available to the model.
@@ -165,6 +502,7 @@ type ToolFunction struct {
 
 // ToolCall represents a model's request to call a tool.
 type ToolCall struct {
+	Index    *int             `json:"index,omitempty"` // Position of the call; set in stream deltas
 	ID       string           `json:"id"`
 	Type     string           `json:"type"`
 	Function ToolCallFunction `json:"function"`
@@ -189,6 +527,9 @@ type ChatCompletionResponse struct {
 	Usage             *Usage   `json:"usage,omitempty"`
 	SystemFingerprint string   `json:"system_fingerprint,omitempty"`
 	Provider          string   `json:"provider,omitempty"` // OpenRouter provider used
+
+	// Set on stream chunks when the provider fails mid-stream.
+	Error *ErrorDetails `json:"error,omitempty"`
 }
 
 // Choice represents a single completion choice.
@@ -196,7 +537,7 @@ type Choice struct {
 	Index        int          `json:"index"`
 	Message      *ChatMessage `json:"message,omitempty"` // Present in non-stream
 	Delta        *ChatMessage `json:"delta,omitempty"`   // Present in stream
-	FinishReason string       `json:"finish_reason"`     // stop, length, tool_calls, content_filter
+	FinishReason string       `json:"finish_reason"`     // stop, length, tool_calls, content_filter, error
 }
 
 // Usage provides token counts and cost information.
@@ -205,6 +546,78 @@ type Usage struct {
 	CompletionTokens int     `json:"completion_tokens"`
 	TotalTokens      int     `json:"total_tokens"`
 	TotalCost        float64 `json:"total_cost,omitempty"` // OpenRouter specific: cost in USD
+
+	// Credits charged for the request, in USD (with usage accounting enabled).
+	Cost float64 `json:"cost,omitempty"`
+
+	// Whether the request was billed to the caller's own provider key.
+	IsBYOK bool `json:"is_byok,omitempty"`
+
+	// Breakdown of the prompt tokens.
+	PromptTokensDetails *PromptTokensDetails `json:"prompt_tokens_details,omitempty"`
+
+	// Breakdown of the completion tokens.
+	CompletionTokensDetails *CompletionTokensDetails `json:"completion_tokens_details,omitempty"`
+
+	// Breakdown of the cost.
+	CostDetails *CostDetails `json:"cost_details,omitempty"`
+}
+
+// PromptTokensDetails breaks down the prompt tokens.
+type PromptTokensDetails struct {
+	// Tokens read from the prompt cache.
+	CachedTokens int `json:"cached_tokens"`
+
+	// Tokens written to the prompt cache.
+	CacheWriteTokens int `json:"cache_write_tokens,omitempty"`
+
+	// Tokens used by audio input.
+	AudioTokens int `json:"audio_tokens,omitempty"`
+}
+
+// CompletionTokensDetails breaks down the completion tokens.
+type CompletionTokensDetails struct {
+	// Tokens spent on reasoning.
+	ReasoningTokens int `json:"reasoning_tokens"`
+
+	// Tokens used by image output.
+	ImageTokens int `json:"image_tokens,omitempty"`
+}
+
+// CostDetails breaks down the cost of a request.
+type CostDetails struct {
+	// Cost charged by the upstream provider, for BYOK requests.
+	UpstreamInferenceCost            *float64 `json:"upstream_inference_cost,omitempty"`
+	UpstreamInferencePromptCost      *float64 `json:"upstream_inference_prompt_cost,omitempty"`
+	UpstreamInferenceCompletionsCost *float64 `json:"upstream_inference_completions_cost,omitempty"`
+}
+
+// BilledCost returns the cost of the request in USD, preferring Cost and
+// falling back to TotalCost.
+func (u *Usage) BilledCost() float64 {
+	if u == nil {
+		return 0
+	}
+	if u.Cost != 0 {
+		return u.Cost
+	}
+	return u.TotalCost
+}
+
+// ReasoningTokens returns the completion tokens spent on reasoning.
+func (u *Usage) ReasoningTokens() int {
+	if u == nil || u.CompletionTokensDetails == nil {
+		return 0
+	}
+	return u.CompletionTokensDetails.ReasoningTokens
+}
+
+// CachedTokens returns the prompt tokens served from the cache.
+func (u *Usage) CachedTokens() int {
+	if u == nil || u.PromptTokensDetails == nil {
+		return 0
+	}
+	return u.PromptTokensDetails.CachedTokens
 }
 
 // ErrorResponse represents an API error.
@@ -219,3 +632,252 @@ type ErrorDetails struct {
 	Code     interface{}            `json:"code,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"` // OpenRouter specific metadata
 }
+
+// -----------------------------------------------------------------------------
+// Generation Stats API (GET /generation)
+// -----------------------------------------------------------------------------
+
+// GenerationResponse represents the response from the /generation endpoint.
+type GenerationResponse struct {
+	Data GenerationStats `json:"data"`
+}
+
+// GenerationStats holds the authoritative accounting for a single generation.
+type GenerationStats struct {
+	// The generation ID (same as ChatCompletionResponse.ID).
+	ID string `json:"id"`
+
+	// Total cost of the generation in USD.
+	TotalCost float64 `json:"total_cost"`
+
+	// Timestamp of the generation (RFC 3339).
+	CreatedAt string `json:"created_at"`
+
+	// The model that served the request.
+	Model string `json:"model"`
+
+	// The origin URL (HTTP-Referer) of the request.
+	Origin string `json:"origin,omitempty"`
+
+	// Amount of credits used.
+	Usage float64 `json:"usage"`
+
+	// Whether the request used the caller's own provider key.
+	IsBYOK bool `json:"is_byok"`
+
+	// The upstream provider's ID for the generation.
+	UpstreamID string `json:"upstream_id,omitempty"`
+
+	// Discount applied due to prompt caching, in USD.
+	CacheDiscount *float64 `json:"cache_discount,omitempty"`
+
+	// Cost charged by the upstream provider (BYOK), in USD.
+	UpstreamInferenceCost *float64 `json:"upstream_inference_cost,omitempty"`
+
+	// ID of the app that made the request, if any.
+	AppID *int `json:"app_id,omitempty"`
+
+	Streamed  bool `json:"streamed"`
+	Cancelled bool `json:"cancelled"`
+
+	// The provider that served the request.
+	ProviderName string `json:"provider_name"`
+
+	// Total latency, moderation latency and generation time in milliseconds.
+	Latency           *int `json:"latency,omitempty"`
+	ModerationLatency *int `json:"moderation_latency,omitempty"`
+	GenerationTime    *int `json:"generation_time,omitempty"`
+
+	FinishReason       string `json:"finish_reason,omitempty"`
+	NativeFinishReason string `json:"native_finish_reason,omitempty"`
+
+	// Token counts as normalized by OpenRouter.
+	TokensPrompt     int `json:"tokens_prompt"`
+	TokensCompletion int `json:"tokens_completion"`
+
+	// Token counts as reported by the model's native tokenizer.
+	NativeTokensPrompt     int `json:"native_tokens_prompt"`
+	NativeTokensCompletion int `json:"native_tokens_completion"`
+	NativeTokensReasoning  int `json:"native_tokens_reasoning"`
+	NativeTokensCached     int `json:"native_tokens_cached"`
+
+	NumMediaPrompt     int `json:"num_media_prompt"`
+	NumMediaCompletion int `json:"num_media_completion"`
+	NumSearchResults   int `json:"num_search_results"`
+}
+
+// -----------------------------------------------------------------------------
+// Credits API (GET /credits)
+// -----------------------------------------------------------------------------
+
+// CreditsResponse represents the response from the /credits endpoint.
+type CreditsResponse struct {
+	Data Credits `json:"data"`
+}
+
+// Credits holds the account's credit balance in USD.
+type Credits struct {
+	// Total credits purchased.
+	TotalCredits float64 `json:"total_credits"`
+
+	// Total credits used.
+	TotalUsage float64 `json:"total_usage"`
+}
+
+// Remaining returns the unused credit balance.
+func (c Credits) Remaining() float64 {
+	return c.TotalCredits - c.TotalUsage
+}
+
+// -----------------------------------------------------------------------------
+// API Key Info API (GET /key)
+// -----------------------------------------------------------------------------
+
+// KeyInfoResponse represents the response from the /key endpoint.
+type KeyInfoResponse struct {
+	Data KeyInfo `json:"data"`
+}
+
+// KeyInfo describes the API key used for the request.
+type KeyInfo struct {
+	// Name of the key.
+	Label string `json:"label"`
+
+	// Credits used by the key, in USD.
+	Usage float64 `json:"usage"`
+
+	// Usage for the current UTC day, week and month, in USD.
+	UsageDaily   float64 `json:"usage_daily,omitempty"`
+	UsageWeekly  float64 `json:"usage_weekly,omitempty"`
+	UsageMonthly float64 `json:"usage_monthly,omitempty"`
+
+	// Credit limit of the key, or nil if unlimited.
+	Limit *float64 `json:"limit"`
+
+	// Credits left under the limit, or nil if unlimited.
+	LimitRemaining *float64 `json:"limit_remaining"`
+
+	// How often the limit resets (e.g. "daily", "monthly"), if it does.
+	LimitReset string `json:"limit_reset,omitempty"`
+
+	// Whether the account has never purchased credits.
+	IsFreeTier bool `json:"is_free_tier"`
+
+	// Whether this is a provisioning key.
+	IsProvisioningKey bool `json:"is_provisioning_key,omitempty"`
+
+	// Rate limit applied to the key.
+	RateLimit *KeyRateLimit `json:"rate_limit,omitempty"`
+}
+
+// KeyRateLimit describes a request budget over a time window.
+type KeyRateLimit struct {
+	// Number of requests allowed per interval.
+	Requests int `json:"requests"`
+
+	// Window length as a duration string (e.g. "10s").
+	Interval string `json:"interval"`
+}
+
+// Window parses Interval into a time.Duration.
+func (r KeyRateLimit) Window() (time.Duration, error) {
+	return time.ParseDuration(r.Interval)
+}
+
+// -----------------------------------------------------------------------------
+// API Keys Provisioning API (/keys)
+// -----------------------------------------------------------------------------
+
+// APIKey describes an API key managed through the provisioning API.
+type APIKey struct {
+	// Identifier of the key used by the provisioning endpoints.
+	Hash string `json:"hash"`
+
+	// Name of the key.
+	Name string `json:"name"`
+
+	// Display label of the key (a redacted form of the key).
+	Label string `json:"label"`
+
+	// Whether the key is disabled.
+	Disabled bool `json:"disabled"`
+
+	// Credit limit of the key in USD, or nil if unlimited.
+	Limit *float64 `json:"limit"`
+
+	// Credits left under the limit, or nil if unlimited.
+	LimitRemaining *float64 `json:"limit_remaining,omitempty"`
+
+	// How often the limit resets (e.g. "daily", "weekly", "monthly"), if it does.
+	LimitReset *string `json:"limit_reset,omitempty"`
+
+	// Whether usage billed to the caller's own provider keys counts against the limit.
+	IncludeBYOKInLimit bool `json:"include_byok_in_limit,omitempty"`
+
+	// Credits used by the key, in USD.
+	Usage        float64 `json:"usage"`
+	UsageDaily   float64 `json:"usage_daily,omitempty"`
+	UsageWeekly  float64 `json:"usage_weekly,omitempty"`
+	UsageMonthly float64 `json:"usage_monthly,omitempty"`
+
+	// Timestamps (RFC 3339).
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt *string `json:"updated_at,omitempty"`
+}
+
+// ListKeysOptions filters the result of KeysService.List.
+type ListKeysOptions struct {
+	// Number of keys to skip, for pagination.
+	Offset int
+
+	// Whether to include disabled keys.
+	IncludeDisabled bool
+}
+
+// ListKeysResponse represents the response from GET /keys.
+type ListKeysResponse struct {
+	Data []APIKey `json:"data"`
+}
+
+// CreateKeyRequest is the body of POST /keys.
+type CreateKeyRequest struct {
+	// Name of the new key.
+	Name string `json:"name"`
+
+	// Optional credit limit in USD.
+	Limit *float64 `json:"limit,omitempty"`
+
+	// Optional limit reset period ("daily", "weekly" or "monthly").
+	LimitReset string `json:"limit_reset,omitempty"`
+
+	// Whether BYOK usage counts against the limit.
+	IncludeBYOKInLimit *bool `json:"include_byok_in_limit,omitempty"`
+}
+
+// CreateKeyResponse represents the response from POST /keys.
+type CreateKeyResponse struct {
+	Data APIKey `json:"data"`
+
+	// The secret key. It is only returned once, on creation.
+	Key string `json:"key"`
+}
+
+// UpdateKeyRequest is the body of PATCH /keys/{hash}.
+// Nil fields are left unchanged.
+type UpdateKeyRequest struct {
+	Name               *string  `json:"name,omitempty"`
+	Disabled           *bool    `json:"disabled,omitempty"`
+	Limit              *float64 `json:"limit,omitempty"`
+	LimitReset         *string  `json:"limit_reset,omitempty"`
+	IncludeBYOKInLimit *bool    `json:"include_byok_in_limit,omitempty"`
+}
+
+// APIKeyResponse represents a response wrapping a single key.
+type APIKeyResponse struct {
+	Data APIKey `json:"data"`
+}
+
+// DeleteKeyResponse represents the response from DELETE /keys/{hash}.
+type DeleteKeyResponse struct {
+	Deleted bool `json:"deleted"`
+}
@@ -0,0 +1,220 @@
+package openrouter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Size limits enforced by the content part constructors.
+var (
+	MaxImageBytes int64 = 20 << 20
+	MaxFileBytes  int64 = 50 << 20
+	MaxAudioBytes int64 = 25 << 20
+)
+
+// Supported image MIME types and audio file extensions.
+var (
+	imageMIMETypes  = []string{"image/png", "image/jpeg", "image/webp", "image/gif"}
+	audioExtensions = map[string]string{
+		".wav":  "wav",
+		".mp3":  "mp3",
+		".aif":  "aiff",
+		".aiff": "aiff",
+		".aac":  "aac",
+		".ogg":  "ogg",
+		".oga":  "ogg",
+		".flac": "flac",
+		".m4a":  "m4a",
+	}
+)
+
+// TextPart returns a text content part.
+func TextPart(text string) ContentPart {
+	return ContentPart{Type: "text", Text: text}
+}
+
+// ImagePartFromURL returns an image content part referencing a URL or data URL.
+// detail may be "auto", "low", "high" or empty.
+func ImagePartFromURL(url, detail string) ContentPart {
+	return ContentPart{Type: "image_url", ImageURL: &ImageURL{URL: url, Detail: detail}}
+}
+
+// ImagePartFromFile reads a local image and embeds it as a data URL.
+func ImagePartFromFile(path string) (ContentPart, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ContentPart{}, err
+	}
+	defer f.Close()
+
+	return ImagePartFromReader(f, "")
+}
+
+// ImagePartFromReader reads an image and embeds it as a data URL. If mimeType
+// is empty it is sniffed from the content.
+func ImagePartFromReader(r io.Reader, mimeType string) (ContentPart, error) {
+	data, err := readLimited(r, MaxImageBytes)
+	if err != nil {
+		return ContentPart{}, fmt.Errorf("image: %w", err)
+	}
+
+	mimeType = resolveMIME(mimeType, data)
+	if !slices.Contains(imageMIMETypes, mimeType) {
+		return ContentPart{}, fmt.Errorf("image: unsupported type %q", mimeType)
+	}
+
+	return ImagePartFromURL(dataURL(mimeType, data), ""), nil
+}
+
+// FilePartFromPDF reads a local PDF and embeds it as a file content part.
+func FilePartFromPDF(path string) (ContentPart, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ContentPart{}, err
+	}
+	defer f.Close()
+
+	return FilePartFromReader(filepath.Base(path), f)
+}
+
+// FilePartFromReader reads a PDF and embeds it as a file content part.
+func FilePartFromReader(filename string, r io.Reader) (ContentPart, error) {
+	data, err := readLimited(r, MaxFileBytes)
+	if err != nil {
+		return ContentPart{}, fmt.Errorf("file: %w", err)
+	}
+
+	if mimeType := http.DetectContentType(data); mimeType != "application/pdf" {
+		return ContentPart{}, fmt.Errorf("file: expected a PDF, got %q", mimeType)
+	}
+
+	return ContentPart{
+		Type: "file",
+		File: &FileData{
+			Filename: filename,
+			FileData: dataURL("application/pdf", data),
+		},
+	}, nil
+}
+
+// AudioPartFromFile reads a local audio file and embeds it as an input_audio
+// content part. The format is taken from the content or file extension.
+func AudioPartFromFile(path string) (ContentPart, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ContentPart{}, err
+	}
+	defer f.Close()
+
+	data, err := readLimited(f, MaxAudioBytes)
+	if err != nil {
+		return ContentPart{}, fmt.Errorf("audio: %w", err)
+	}
+
+	format := sniffAudio(data)
+	if format == "" {
+		format = audioExtensions[strings.ToLower(filepath.Ext(path))]
+	}
+	if format == "" {
+		return ContentPart{}, fmt.Errorf("audio: unsupported format for %s", filepath.Base(path))
+	}
+
+	return AudioPart(data, format), nil
+}
+
+// AudioPart returns an input_audio content part for raw audio bytes in the
+// given format (e.g. "wav", "mp3").
+func AudioPart(data []byte, format string) ContentPart {
+	return ContentPart{
+		Type: "input_audio",
+		InputAudio: &InputAudio{
+			Data:   base64.StdEncoding.EncodeToString(data),
+			Format: format,
+		},
+	}
+}
+
+// NewMultimodalMessage builds a message with a text part followed by parts.
+// The text part is omitted when text is empty.
+func NewMultimodalMessage(role, text string, parts ...ContentPart) ChatMessage {
+	content := make([]ContentPart, 0, len(parts)+1)
+	if text != "" {
+		content = append(content, TextPart(text))
+	}
+	content = append(content, parts...)
+	return ChatMessage{Role: role, Content: content}
+}
+
+// NewUserMessage is a shorthand for NewMultimodalMessage with the user role.
+func NewUserMessage(text string, parts ...ContentPart) ChatMessage {
+	return NewMultimodalMessage("user", text, parts...)
+}
+
+// -----------------------------------------------------------------------------
+// Internal Helpers
+// -----------------------------------------------------------------------------
+
+// readLimited reads all of r, failing if it holds more than limit bytes.
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	var buf bytes.Buffer
+	n, err := buf.ReadFrom(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("empty input")
+	}
+	if n > limit {
+		return nil, fmt.Errorf("input exceeds %d bytes", limit)
+	}
+	return buf.Bytes(), nil
+}
+
+// resolveMIME returns the declared type, or the sniffed one when it is empty
+// or generic.
+func resolveMIME(declared string, data []byte) string {
+	declared, _, _ = strings.Cut(declared, ";")
+	if declared != "" && declared != "application/octet-stream" {
+		return declared
+	}
+	sniffed, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	return sniffed
+}
+
+// sniffAudio detects the input_audio format from the magic bytes of data, or
+// returns "" if it is not recognized.
+func sniffAudio(data []byte) string {
+	switch {
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WAVE":
+		return "wav"
+	case len(data) >= 12 && string(data[:4]) == "FORM" &&
+		(string(data[8:12]) == "AIFF" || string(data[8:12]) == "AIFC"):
+		return "aiff"
+	case bytes.HasPrefix(data, []byte("fLaC")):
+		return "flac"
+	case bytes.HasPrefix(data, []byte("OggS")):
+		return "ogg"
+	case len(data) >= 8 && string(data[4:8]) == "ftyp":
+		return "m4a"
+	case bytes.HasPrefix(data, []byte("ID3")):
+		return "mp3"
+	case len(data) >= 2 && data[0] == 0xFF && data[1]&0xF6 == 0xF0:
+		// ADTS frame sync with layer 0.
+		return "aac"
+	case len(data) >= 2 && data[0] == 0xFF && data[1]&0xE0 == 0xE0 && data[1]&0x06 != 0:
+		// MPEG audio frame sync with layer I-III.
+		return "mp3"
+	}
+	return ""
+}
+
+func dataURL(mimeType string, data []byte) string {
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
@@ -0,0 +1,75 @@
+package openrouter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSniffAudio(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"wav", "RIFF\x24\x00\x00\x00WAVEfmt ", "wav"},
+		{"aiff", "FORM\x00\x00\x00\x00AIFFCOMM", "aiff"},
+		{"aifc", "FORM\x00\x00\x00\x00AIFCFVER", "aiff"},
+		{"flac", "fLaC\x00\x00\x00\x22", "flac"},
+		{"ogg", "OggS\x00\x02\x00\x00", "ogg"},
+		{"m4a", "\x00\x00\x00\x20ftypM4A \x00\x00\x00\x00", "m4a"},
+		{"mp3 with ID3", "ID3\x04\x00\x00\x00\x00\x00\x00", "mp3"},
+		{"mp3 frame", "\xFF\xFB\x90\x64\x00\x00", "mp3"},
+		{"aac adts", "\xFF\xF1\x50\x80\x02\x1F", "aac"},
+		{"text", "hello world", ""},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sniffAudio([]byte(tt.data)); got != tt.want {
+				t.Errorf("sniffAudio = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAudioPartFromFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"sniffed despite extension", write("a.bin", "fLaC\x00\x00\x00\x22"), "flac", false},
+		{"extension fallback", write("b.M4A", "\x00\x01\x02\x03"), "m4a", false},
+		{"unknown", write("c.txt", "not audio"), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			part, err := AudioPartFromFile(tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AudioPartFromFile: %v", err)
+			}
+			if part.Type != "input_audio" || part.InputAudio.Format != tt.want {
+				t.Errorf("part = %+v, want format %q", part.InputAudio, tt.want)
+			}
+		})
+	}
+}
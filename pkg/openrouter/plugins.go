@@ -0,0 +1,48 @@
+package openrouter
+
+// WebSearchPlugin returns the web search plugin. maxResults and searchPrompt
+// may be zero to use OpenRouter's defaults.
+func WebSearchPlugin(maxResults int, searchPrompt string) Plugin {
+	return Plugin{ID: "web", MaxResults: maxResults, SearchPrompt: searchPrompt}
+}
+
+// FileParserPlugin returns the file-parser plugin using the given PDF engine
+// ("pdf-text", "mistral-ocr" or "native").
+func FileParserPlugin(pdfEngine string) Plugin {
+	return Plugin{ID: "file-parser", PDF: &PDFOptions{Engine: pdfEngine}}
+}
+
+// ResponseHealingPlugin returns the plugin that repairs malformed JSON in
+// structured responses.
+func ResponseHealingPlugin() Plugin {
+	return Plugin{ID: "response-healing"}
+}
+
+// Citations returns the URL citations attached to the message, without duplicates.
+func (m *ChatMessage) Citations() []URLCitation {
+	var citations []URLCitation
+	seen := make(map[string]bool)
+	for _, a := range m.Annotations {
+		if a.Type != "url_citation" || a.URLCitation == nil || seen[a.URLCitation.URL] {
+			continue
+		}
+		seen[a.URLCitation.URL] = true
+		citations = append(citations, *a.URLCitation)
+	}
+	return citations
+}
+
+// Citations returns the URL citations of the first choice.
+func (r *ChatCompletionResponse) Citations() []URLCitation {
+	if len(r.Choices) == 0 {
+		return nil
+	}
+	msg := r.Choices[0].Message
+	if msg == nil {
+		msg = r.Choices[0].Delta
+	}
+	if msg == nil {
+		return nil
+	}
+	return msg.Citations()
+}
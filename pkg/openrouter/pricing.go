@@ -0,0 +1,325 @@
+package openrouter
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// maxDecimalPlaces bounds the digits printed for non-terminating decimals.
+const maxDecimalPlaces = 20
+
+// Decimal is an exact decimal number used for prices and costs.
+// The zero value is 0.
+type Decimal struct {
+	r *big.Rat
+}
+
+// ParseDecimal parses a decimal string such as "0.000003" or "1e-6" exactly.
+// An empty string parses as zero.
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Decimal{}, nil
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	return Decimal{r: r}, nil
+}
+
+// NewDecimalFromInt returns the Decimal for n.
+func NewDecimalFromInt(n int64) Decimal {
+	return Decimal{r: new(big.Rat).SetInt64(n)}
+}
+
+func (d Decimal) rat() *big.Rat {
+	if d.r == nil {
+		return new(big.Rat)
+	}
+	return d.r
+}
+
+// Add returns d + o.
+func (d Decimal) Add(o Decimal) Decimal {
+	return Decimal{r: new(big.Rat).Add(d.rat(), o.rat())}
+}
+
+// Sub returns d - o.
+func (d Decimal) Sub(o Decimal) Decimal {
+	return Decimal{r: new(big.Rat).Sub(d.rat(), o.rat())}
+}
+
+// Mul returns d * o.
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{r: new(big.Rat).Mul(d.rat(), o.rat())}
+}
+
+// MulInt returns d * n.
+func (d Decimal) MulInt(n int64) Decimal {
+	return d.Mul(NewDecimalFromInt(n))
+}
+
+// Cmp compares d and o and returns -1, 0 or +1.
+func (d Decimal) Cmp(o Decimal) int {
+	return d.rat().Cmp(o.rat())
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	return d.rat().Sign()
+}
+
+// IsZero reports whether d is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Rat returns a copy of d as a *big.Rat.
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).Set(d.rat())
+}
+
+// Float64 returns the nearest float64 to d.
+func (d Decimal) Float64() float64 {
+	f, _ := d.rat().Float64()
+	return f
+}
+
+// String formats d in plain decimal notation, exactly when possible.
+func (d Decimal) String() string {
+	r := d.rat()
+	if r.IsInt() {
+		return r.Num().String()
+	}
+
+	// A fraction terminates when its denominator only has factors 2 and 5;
+	// the number of places needed is the larger of the two exponents.
+	den := new(big.Int).Set(r.Denom())
+	var twos, fives int
+	zero, two, five := big.NewInt(0), big.NewInt(2), big.NewInt(5)
+	mod := new(big.Int)
+	for mod.Mod(den, two).Cmp(zero) == 0 {
+		den.Quo(den, two)
+		twos++
+	}
+	for mod.Mod(den, five).Cmp(zero) == 0 {
+		den.Quo(den, five)
+		fives++
+	}
+
+	if den.Cmp(big.NewInt(1)) != 0 {
+		return strings.TrimRight(strings.TrimRight(r.FloatString(maxDecimalPlaces), "0"), ".")
+	}
+	return r.FloatString(max(twos, fives))
+}
+
+// -----------------------------------------------------------------------------
+// Pricing
+// -----------------------------------------------------------------------------
+
+// ModelPrices is the parsed, exact form of ModelPricing, in USD.
+type ModelPrices struct {
+	Prompt            Decimal // per input token
+	Completion        Decimal // per output token
+	Image             Decimal // per input image
+	Request           Decimal // per request
+	WebSearch         Decimal // per web search
+	InternalReasoning Decimal // per reasoning token
+	InputCacheRead    Decimal // per cached input token read
+	InputCacheWrite   Decimal // per input token written to the cache
+}
+
+// Parse converts the pricing strings into exact decimals.
+// Negative prices, which OpenRouter uses for variably priced routers such as
+// "openrouter/auto", are reported as an error.
+func (p ModelPricing) Parse() (ModelPrices, error) {
+	var prices ModelPrices
+	fields := []struct {
+		name string
+		raw  string
+		dst  *Decimal
+	}{
+		{"prompt", p.Prompt, &prices.Prompt},
+		{"completion", p.Completion, &prices.Completion},
+		{"image", p.Image, &prices.Image},
+		{"request", p.Request, &prices.Request},
+		{"web_search", p.WebSearch, &prices.WebSearch},
+		{"internal_reasoning", p.InternalReasoning, &prices.InternalReasoning},
+		{"input_cache_read", p.InputCacheRead, &prices.InputCacheRead},
+		{"input_cache_write", p.InputCacheWrite, &prices.InputCacheWrite},
+	}
+
+	for _, f := range fields {
+		d, err := ParseDecimal(f.raw)
+		if err != nil {
+			return ModelPrices{}, fmt.Errorf("failed to parse %s price: %w", f.name, err)
+		}
+		if d.Sign() < 0 {
+			return ModelPrices{}, fmt.Errorf("%s price is variable (%s)", f.name, f.raw)
+		}
+		*f.dst = d
+	}
+
+	return prices, nil
+}
+
+// CostEstimate breaks down the cost of a request in USD.
+type CostEstimate struct {
+	Prompt     Decimal // uncached prompt tokens
+	CacheRead  Decimal // prompt tokens read from the cache
+	CacheWrite Decimal // prompt tokens written to the cache
+	Completion Decimal // output tokens, excluding reasoning billed separately
+	Reasoning  Decimal // reasoning tokens, when the model prices them separately
+	Images     Decimal
+	Request    Decimal
+	Total      Decimal
+
+	// Amount saved by cache reads compared to the full prompt price.
+	CacheSavings Decimal
+}
+
+// sum sets Total from the individual components.
+func (c *CostEstimate) sum() {
+	c.Total = c.Prompt.Add(c.CacheRead).Add(c.CacheWrite).Add(c.Completion).Add(c.Reasoning).
+		Add(c.Images).Add(c.Request)
+}
+
+// EstimateCost computes the cost of a completed request from its usage,
+// pricing cached prompt tokens at the cache read and write rates and, when the
+// model has an internal_reasoning price, reasoning tokens at that rate.
+func EstimateCost(model Model, usage Usage) (*CostEstimate, error) {
+	prices, err := model.Pricing.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	var cached, written int64
+	if d := usage.PromptTokensDetails; d != nil {
+		cached, written = int64(d.CachedTokens), int64(d.CacheWriteTokens)
+	}
+	uncached := max(int64(usage.PromptTokens)-cached-written, 0)
+
+	// Providers without cache prices bill reads and writes as regular input.
+	readPrice, writePrice := prices.InputCacheRead, prices.InputCacheWrite
+	if readPrice.IsZero() {
+		readPrice = prices.Prompt
+	}
+	if writePrice.IsZero() {
+		writePrice = prices.Prompt
+	}
+
+	completion, reasoning := splitReasoning(prices, int64(usage.CompletionTokens), int64(usage.ReasoningTokens()))
+
+	est := &CostEstimate{
+		Prompt:       prices.Prompt.MulInt(uncached),
+		CacheRead:    readPrice.MulInt(cached),
+		CacheWrite:   writePrice.MulInt(written),
+		Completion:   prices.Completion.MulInt(completion),
+		Reasoning:    prices.InternalReasoning.MulInt(reasoning),
+		Request:      prices.Request,
+		CacheSavings: prices.Prompt.Sub(readPrice).MulInt(cached),
+	}
+	est.sum()
+
+	return est, nil
+}
+
+// EstimateRequestCost estimates the cost of req before it is sent.
+// Prompt tokens are approximated from the message text, and the completion is
+// assumed to use all of req.MaxTokens, or else the top provider's completion
+// limit, or else the rest of the context window, so the result is an upper
+// bound for most requests. It fails if none of these is known. Up to
+// req.Reasoning.MaxTokens of the completion are priced as reasoning.
+func EstimateRequestCost(model Model, req ChatCompletionRequest) (*CostEstimate, error) {
+	prices, err := model.Pricing.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	promptTokens, images := approxPromptTokens(req)
+
+	completionTokens := req.MaxTokens
+	if completionTokens == 0 && model.TopProvider.MaxCompletionTokens != nil {
+		completionTokens = *model.TopProvider.MaxCompletionTokens
+	}
+	if completionTokens == 0 {
+		// Without a completion limit, the rest of the context is the bound.
+		contextLength := model.ContextLength
+		if model.TopProvider.ContextLength != nil {
+			contextLength = *model.TopProvider.ContextLength
+		}
+		completionTokens = contextLength - promptTokens
+	}
+	if completionTokens <= 0 {
+		return nil, fmt.Errorf("no completion token bound known for model %q; set MaxTokens", model.ID)
+	}
+
+	var reasoningTokens int
+	if req.Reasoning != nil {
+		reasoningTokens = min(req.Reasoning.MaxTokens, completionTokens)
+	}
+	completion, reasoning := splitReasoning(prices, int64(completionTokens), int64(reasoningTokens))
+
+	est := &CostEstimate{
+		Prompt:     prices.Prompt.MulInt(int64(promptTokens)),
+		Completion: prices.Completion.MulInt(completion),
+		Reasoning:  prices.InternalReasoning.MulInt(reasoning),
+		Images:     prices.Image.MulInt(int64(images)),
+		Request:    prices.Request,
+	}
+	est.sum()
+
+	return est, nil
+}
+
+// -----------------------------------------------------------------------------
+// Internal Helpers
+// -----------------------------------------------------------------------------
+
+// splitReasoning separates the reasoning tokens, which are included in the
+// completion tokens, when the model prices them separately.
+func splitReasoning(prices ModelPrices, completion, reasoning int64) (int64, int64) {
+	if prices.InternalReasoning.IsZero() || reasoning <= 0 {
+		return completion, 0
+	}
+	reasoning = min(reasoning, completion)
+	return completion - reasoning, reasoning
+}
+
+const (
+	charsPerToken      = 4 // rough average for English text
+	tokensPerMessage   = 4 // role and separator overhead
+	tokensPerToolField = 8 // tool wrapper overhead
+)
+
+// approxPromptTokens roughly estimates the prompt tokens of req and counts its
+// image parts.
+func approxPromptTokens(req ChatCompletionRequest) (tokens, images int) {
+	chars := 0
+	for _, msg := range req.Messages {
+		tokens += tokensPerMessage
+		switch content := msg.Content.(type) {
+		case string:
+			chars += len(content)
+		case []ContentPart:
+			for _, part := range content {
+				chars += len(part.Text)
+				if part.ImageURL != nil {
+					images++
+				}
+			}
+		}
+		for _, tc := range msg.ToolCalls {
+			chars += len(tc.Function.Name) + len(tc.Function.Arguments)
+		}
+	}
+	for _, tool := range req.Tools {
+		tokens += tokensPerToolField
+		chars += len(tool.Function.Name) + len(tool.Function.Description) + len(tool.Function.Parameters)
+	}
+
+	tokens += (chars + charsPerToken - 1) / charsPerToken
+	return tokens, images
+}
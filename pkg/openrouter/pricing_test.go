@@ -0,0 +1,134 @@
+package openrouter
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEstimateCost(t *testing.T) {
+	tests := []struct {
+		name    string
+		pricing ModelPricing
+		usage   Usage
+		want    map[string]string
+	}{
+		{
+			name:    "plain",
+			pricing: ModelPricing{Prompt: "0.000001", Completion: "0.000002"},
+			usage:   Usage{PromptTokens: 1000, CompletionTokens: 500},
+			want:    map[string]string{"prompt": "0.001", "completion": "0.001", "total": "0.002"},
+		},
+		{
+			name:    "cache read price",
+			pricing: ModelPricing{Prompt: "0.000001", InputCacheRead: "0.0000001"},
+			usage: Usage{
+				PromptTokens:        1000,
+				PromptTokensDetails: &PromptTokensDetails{CachedTokens: 800},
+			},
+			want: map[string]string{"prompt": "0.0002", "cache_read": "0.00008", "savings": "0.00072", "total": "0.00028"},
+		},
+		{
+			name:    "cached tokens without a cache read price",
+			pricing: ModelPricing{Prompt: "0.000001"},
+			usage: Usage{
+				PromptTokens:        1000,
+				PromptTokensDetails: &PromptTokensDetails{CachedTokens: 800},
+			},
+			want: map[string]string{"prompt": "0.0002", "cache_read": "0.0008", "savings": "0", "total": "0.001"},
+		},
+		{
+			name:    "reasoning priced separately",
+			pricing: ModelPricing{Prompt: "0.000001", Completion: "0.000002", InternalReasoning: "0.000004"},
+			usage: Usage{
+				PromptTokens:            1000,
+				CompletionTokens:        500,
+				CompletionTokensDetails: &CompletionTokensDetails{ReasoningTokens: 200},
+			},
+			want: map[string]string{"completion": "0.0006", "reasoning": "0.0008", "total": "0.0024"},
+		},
+		{
+			name:    "reasoning without a separate price",
+			pricing: ModelPricing{Prompt: "0.000001", Completion: "0.000002"},
+			usage: Usage{
+				PromptTokens:            1000,
+				CompletionTokens:        500,
+				CompletionTokensDetails: &CompletionTokensDetails{ReasoningTokens: 200},
+			},
+			want: map[string]string{"completion": "0.001", "reasoning": "0", "total": "0.002"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			est, err := EstimateCost(Model{Pricing: tt.pricing}, tt.usage)
+			if err != nil {
+				t.Fatalf("EstimateCost: %v", err)
+			}
+			got := map[string]Decimal{
+				"prompt":     est.Prompt,
+				"cache_read": est.CacheRead,
+				"completion": est.Completion,
+				"reasoning":  est.Reasoning,
+				"savings":    est.CacheSavings,
+				"total":      est.Total,
+			}
+			for field, want := range tt.want {
+				if s := got[field].String(); s != want {
+					t.Errorf("%s = %s, want %s", field, s, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEstimateRequestCostReasoning(t *testing.T) {
+	model := Model{Pricing: ModelPricing{Completion: "0.000002", InternalReasoning: "0.000004"}}
+	req := ChatCompletionRequest{MaxTokens: 1000, Reasoning: &ReasoningConfig{MaxTokens: 400}}
+
+	est, err := EstimateRequestCost(model, req)
+	if err != nil {
+		t.Fatalf("EstimateRequestCost: %v", err)
+	}
+	if s := est.Completion.String(); s != "0.0012" {
+		t.Errorf("completion = %s, want 0.0012", s)
+	}
+	if s := est.Reasoning.String(); s != "0.0016" {
+		t.Errorf("reasoning = %s, want 0.0016", s)
+	}
+}
+
+func TestEstimateRequestCostCompletionBound(t *testing.T) {
+	req := ChatCompletionRequest{Messages: []ChatMessage{{Role: "user", Content: "hello there"}}}
+	prompt, _ := approxPromptTokens(req)
+	providerContext := 500
+
+	tests := []struct {
+		name    string
+		model   Model
+		want    int
+		wantErr bool
+	}{
+		{"model context", Model{ContextLength: 1000}, 1000 - prompt, false},
+		{"provider context", Model{ContextLength: 1000, TopProvider: ProviderInfo{ContextLength: &providerContext}}, 500 - prompt, false},
+		{"no bound", Model{}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.model.Pricing = ModelPricing{Completion: "1"}
+			est, err := EstimateRequestCost(tt.model, req)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EstimateRequestCost: %v", err)
+			}
+			if got, want := est.Completion.String(), fmt.Sprint(tt.want); got != want {
+				t.Errorf("completion = %s, want %s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package openrouter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Accepted values for ProviderPreferences fields.
+var (
+	providerSorts  = []string{"price", "throughput", "latency"}
+	quantizations  = []string{"int4", "int8", "fp4", "fp6", "fp8", "fp16", "bf16", "fp32", "unknown"}
+	dataCollection = []string{"allow", "deny"}
+)
+
+// Validate checks the preferences for invalid values and contradictions, such
+// as a provider that is both required and ignored.
+func (p *ProviderPreferences) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	if p.DataCollection != "" && !slices.Contains(dataCollection, p.DataCollection) {
+		return fmt.Errorf("provider: invalid data_collection %q (want %s)", p.DataCollection, strings.Join(dataCollection, " or "))
+	}
+	if p.Sort != "" && !slices.Contains(providerSorts, p.Sort) {
+		return fmt.Errorf("provider: invalid sort %q (want one of %s)", p.Sort, strings.Join(providerSorts, ", "))
+	}
+	for _, q := range p.Quantizations {
+		if !slices.Contains(quantizations, q) {
+			return fmt.Errorf("provider: invalid quantization %q (want one of %s)", q, strings.Join(quantizations, ", "))
+		}
+	}
+
+	for _, name := range p.Ignore {
+		if containsFold(p.Only, name) {
+			return fmt.Errorf("provider: %q is in both only and ignore", name)
+		}
+		if containsFold(p.Order, name) {
+			return fmt.Errorf("provider: %q is in both order and ignore", name)
+		}
+	}
+	if p.MaxPrice != nil {
+		prices := []struct {
+			name  string
+			value *float64
+		}{
+			{"prompt", p.MaxPrice.Prompt},
+			{"completion", p.MaxPrice.Completion},
+			{"image", p.MaxPrice.Image},
+			{"request", p.MaxPrice.Request},
+		}
+		for _, price := range prices {
+			if price.value != nil && *price.value < 0 {
+				return fmt.Errorf("provider: max_price.%s must not be negative", price.name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(v string) bool { return strings.EqualFold(v, s) })
+}
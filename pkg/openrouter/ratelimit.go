@@ -0,0 +1,432 @@
+package openrouter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Rate is a request and token budget. Zero fields are unlimited.
+type Rate struct {
+	// Sustained number of requests per second.
+	RequestsPerSecond float64
+
+	// Number of requests that may be sent at once after an idle period
+	// (defaults to RequestsPerSecond rounded up, at least 1).
+	Burst int
+
+	// Number of prompt and completion tokens per minute.
+	TokensPerMinute int
+}
+
+// RateLimit configures the client-side rate limiter.
+type RateLimit struct {
+	// Budget shared by all calls.
+	Global Rate
+
+	// Budget applied to each model ID separately.
+	PerModel Rate
+
+	// Budgets for specific model IDs, replacing PerModel.
+	Models map[string]Rate
+
+	// Budget applied to each API key separately.
+	PerKey Rate
+
+	// Adjust the budgets from the X-RateLimit-* and Retry-After response
+	// headers, and fetch the key's rate limit from /key on its first call.
+	AutoTune bool
+
+	// Estimates the tokens a chat completion consumes. Defaults to a rough
+	// character-based estimate of the prompt plus max_tokens. The estimate is
+	// corrected with the reported usage once the response, or the final
+	// chunk of a stream, arrives.
+	CountTokens func(req ChatCompletionRequest) int
+}
+
+// WithRateLimit throttles calls on the client side so that they stay within
+// limit. Every attempt, including retries, blocks until it fits the budget or
+// its context is done.
+func WithRateLimit(limit RateLimit) Option {
+	return func(c *Client) {
+		c.limiter = newRateLimiter(limit)
+	}
+}
+
+// TuneRateLimit fetches the rate limit of the client's API key from /key and
+// applies it to the key's budget when it is stricter than the configured one.
+// It does nothing if the client has no rate limiter.
+func (c *Client) TuneRateLimit(ctx context.Context) error {
+	if c.limiter == nil {
+		return nil
+	}
+	key := fmt.Sprintf("Bearer %s", c.apiKey)
+	info, err := c.fetchKeyInfo(withoutRateLimit(ctx), key)
+	if err != nil {
+		return err
+	}
+	c.limiter.tuneKey(key, info)
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Internal Helpers
+// -----------------------------------------------------------------------------
+
+type skipRateLimitKey struct{}
+
+// withoutRateLimit marks ctx so that calls made with it are not throttled.
+func withoutRateLimit(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipRateLimitKey{}, true)
+}
+
+// bucket is a token bucket that hands out reservations: taking more than is
+// available leaves a debt that later callers wait for.
+type bucket struct {
+	rate   float64 // units refilled per second
+	size   float64
+	level  float64
+	last   time.Time
+	paused time.Time // no reservation completes before this time
+}
+
+func newBucket(rate, size float64) *bucket {
+	return &bucket{rate: rate, size: size, level: size, last: time.Now()}
+}
+
+func (b *bucket) refill(now time.Time) {
+	if b.rate > 0 {
+		b.level = math.Min(b.size, b.level+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+}
+
+// reserve takes n units and returns how long the caller must wait for them.
+func (b *bucket) reserve(n float64, now time.Time) time.Duration {
+	b.refill(now)
+	b.level -= n
+
+	var wait time.Duration
+	if b.level < 0 && b.rate > 0 {
+		wait = time.Duration(-b.level / b.rate * float64(time.Second))
+	}
+	if d := b.paused.Sub(now); d > wait {
+		wait = d
+	}
+	return wait
+}
+
+// refund returns n units taken by a reservation (n may be negative).
+func (b *bucket) refund(n float64, now time.Time) {
+	b.refill(now)
+	b.level = math.Min(b.size, b.level+n)
+}
+
+func (b *bucket) pause(until time.Time) {
+	if until.After(b.paused) {
+		b.paused = until
+	}
+}
+
+// limits holds the buckets of one scope; either may be nil.
+type limits struct {
+	requests *bucket
+	tokens   *bucket
+}
+
+func newLimits(r Rate) *limits {
+	l := &limits{}
+	if r.RequestsPerSecond > 0 {
+		burst := float64(r.Burst)
+		if burst <= 0 {
+			burst = math.Max(1, math.Ceil(r.RequestsPerSecond))
+		}
+		l.requests = newBucket(r.RequestsPerSecond, burst)
+	}
+	if r.TokensPerMinute > 0 {
+		l.tokens = newBucket(float64(r.TokensPerMinute)/60, float64(r.TokensPerMinute))
+	}
+	return l
+}
+
+type rateLimiter struct {
+	cfg RateLimit
+
+	mu     sync.Mutex
+	global *limits
+	models map[string]*limits
+	keys   map[string]*limits
+	tuned  map[string]bool
+}
+
+func newRateLimiter(cfg RateLimit) *rateLimiter {
+	return &rateLimiter{
+		cfg:    cfg,
+		global: newLimits(cfg.Global),
+		models: make(map[string]*limits),
+		keys:   make(map[string]*limits),
+		tuned:  make(map[string]bool),
+	}
+}
+
+// scopes returns the limits that apply to a call. Callers hold l.mu.
+func (l *rateLimiter) scopes(model, key string) []*limits {
+	scopes := []*limits{l.global}
+	if model != "" {
+		m, ok := l.models[model]
+		if !ok {
+			r, ok := l.cfg.Models[model]
+			if !ok {
+				r = l.cfg.PerModel
+			}
+			m = newLimits(r)
+			l.models[model] = m
+		}
+		scopes = append(scopes, m)
+	}
+	if key != "" {
+		k, ok := l.keys[key]
+		if !ok {
+			k = newLimits(l.cfg.PerKey)
+			l.keys[key] = k
+		}
+		scopes = append(scopes, k)
+	}
+	return scopes
+}
+
+// wait reserves one request and tokens in every scope and blocks until they
+// are available. The reservation is returned if ctx is done first.
+func (l *rateLimiter) wait(ctx context.Context, scopes []*limits, tokens int) error {
+	l.mu.Lock()
+	now := time.Now()
+	var wait time.Duration
+	for _, s := range scopes {
+		if s.requests != nil {
+			wait = max(wait, s.requests.reserve(1, now))
+		}
+		if s.tokens != nil {
+			wait = max(wait, s.tokens.reserve(float64(tokens), now))
+		}
+	}
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.mu.Lock()
+		now := time.Now()
+		for _, s := range scopes {
+			if s.requests != nil {
+				s.requests.refund(1, now)
+			}
+			if s.tokens != nil {
+				s.tokens.refund(float64(tokens), now)
+			}
+		}
+		l.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// settle corrects the token estimate of a call with its actual usage.
+func (l *rateLimiter) settle(scopes []*limits, estimated, actual int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	for _, s := range scopes {
+		if s.tokens != nil {
+			s.tokens.refund(float64(estimated-actual), now)
+		}
+	}
+}
+
+// observe pauses scopes according to the rate-limit headers of a response or
+// the Retry-After of a 429 error. Rate limits reported by an upstream
+// provider pause the model; all others pause the key.
+func (l *rateLimiter) observe(model, key string, header http.Header, err error) {
+	var until time.Time
+	providerLimited := false
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests {
+		header = apiErr.Header
+		providerLimited = apiErr.ProviderName != ""
+		if apiErr.RetryAfter > 0 {
+			until = time.Now().Add(apiErr.RetryAfter)
+		}
+	}
+	if header != nil && header.Get("X-RateLimit-Remaining") == "0" {
+		if reset := parseRateLimitReset(header.Get("X-RateLimit-Reset")); reset.After(until) {
+			until = reset
+		}
+	}
+	if until.IsZero() {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	scope := l.scopes("", key)[1:]
+	if providerLimited && model != "" {
+		scope = l.scopes(model, "")[1:]
+	}
+	for _, s := range scope {
+		if s.requests == nil {
+			s.requests = newBucket(0, 1)
+		}
+		s.requests.pause(until)
+	}
+}
+
+// tuneKey applies the rate limit reported by /key to the key's budget.
+func (l *rateLimiter) tuneKey(key string, info *KeyInfo) {
+	if info == nil || info.RateLimit == nil || info.RateLimit.Requests <= 0 {
+		return
+	}
+	window, err := info.RateLimit.Window()
+	if err != nil || window <= 0 {
+		return
+	}
+	rate := float64(info.RateLimit.Requests) / window.Seconds()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s := l.scopes("", key)[1]
+	if s.requests == nil || s.requests.rate <= 0 || rate < s.requests.rate {
+		b := newBucket(rate, float64(info.RateLimit.Requests))
+		if s.requests != nil {
+			b.paused = s.requests.paused
+		}
+		s.requests = b
+	}
+}
+
+// needsTuning reports, once per key, whether the key's rate limit should be
+// fetched from /key.
+func (l *rateLimiter) needsTuning(key string) bool {
+	if !l.cfg.AutoTune || key == "" {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.tuned[key] {
+		return false
+	}
+	l.tuned[key] = true
+	return true
+}
+
+// limitedCall is the rate-limit state of one call, shared by its attempts.
+type limitedCall struct {
+	limiter *rateLimiter
+	model   string
+	key     string
+	tokens  int // estimated tokens reserved by each attempt
+	scopes  []*limits
+}
+
+// prepare returns the rate-limit state for call, or nil if the call is not
+// throttled. On a key's first call with AutoTune set, it fetches the key's
+// rate limit from /key.
+func (l *rateLimiter) prepare(ctx context.Context, c *Client, call *Call) *limitedCall {
+	if ctx.Value(skipRateLimitKey{}) != nil {
+		return nil
+	}
+
+	key := call.HTTPRequest.Header.Get("Authorization")
+	if l.needsTuning(key) {
+		if info, err := c.fetchKeyInfo(withoutRateLimit(ctx), key); err == nil {
+			l.tuneKey(key, info)
+		}
+	}
+
+	lc := &limitedCall{limiter: l, key: key}
+	if call.Request != nil {
+		lc.model = call.Request.Model
+		lc.tokens = l.countTokens(*call.Request)
+	}
+
+	l.mu.Lock()
+	lc.scopes = l.scopes(lc.model, lc.key)
+	l.mu.Unlock()
+
+	return lc
+}
+
+// before blocks until an attempt fits the budget.
+func (lc *limitedCall) before(ctx context.Context) error {
+	return lc.limiter.wait(ctx, lc.scopes, lc.tokens)
+}
+
+// after records the outcome of an attempt. A failed attempt returns its
+// tokens, and with AutoTune the response headers or a 429 may pause scopes.
+func (lc *limitedCall) after(res *http.Response, err error) {
+	if err != nil {
+		lc.limiter.settle(lc.scopes, lc.tokens, 0)
+	}
+	if lc.limiter.cfg.AutoTune {
+		var header http.Header
+		if res != nil {
+			header = res.Header
+		}
+		lc.limiter.observe(lc.model, lc.key, header, err)
+	}
+}
+
+// settle corrects the token estimate of the successful attempt with the
+// usage reported in the response or on the last chunk of a stream.
+func (lc *limitedCall) settle(usage *Usage) {
+	if usage != nil {
+		lc.limiter.settle(lc.scopes, lc.tokens, usage.TotalTokens)
+	}
+}
+
+func (l *rateLimiter) countTokens(req ChatCompletionRequest) int {
+	if l.cfg.CountTokens != nil {
+		return l.cfg.CountTokens(req)
+	}
+	tokens, _ := approxPromptTokens(req)
+	return tokens + req.MaxTokens
+}
+
+// fetchKeyInfo retrieves /key using the given Authorization header.
+func (c *Client) fetchKeyInfo(ctx context.Context, authorization string) (*KeyInfo, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/key", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", authorization)
+
+	var resp KeyInfoResponse
+	if err := c.sendRequest(req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Data, nil
+}
+
+// parseRateLimitReset parses X-RateLimit-Reset, given as a Unix timestamp in
+// milliseconds (or seconds for small values).
+func parseRateLimitReset(v string) time.Time {
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n <= 0 {
+		return time.Time{}
+	}
+	if n < 1e12 {
+		return time.Unix(n, 0)
+	}
+	return time.UnixMilli(n)
+}
@@ -0,0 +1,172 @@
+package openrouter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBucket(t *testing.T) {
+	t0 := time.Now()
+	b := newBucket(2, 2)
+	b.last = t0
+
+	steps := []struct {
+		name string
+		do   func() time.Duration
+		want time.Duration
+	}{
+		{"burst 1", func() time.Duration { return b.reserve(1, t0) }, 0},
+		{"burst 2", func() time.Duration { return b.reserve(1, t0) }, 0},
+		{"debt", func() time.Duration { return b.reserve(1, t0) }, 500 * time.Millisecond},
+		{"more debt", func() time.Duration { return b.reserve(1, t0) }, time.Second},
+		{"refill", func() time.Duration { return b.reserve(1, t0.Add(time.Second)) }, 500 * time.Millisecond},
+		{"refund", func() time.Duration {
+			b.refund(3, t0.Add(time.Second))
+			return b.reserve(1, t0.Add(time.Second))
+		}, 0},
+		{"pause", func() time.Duration {
+			b.pause(t0.Add(3 * time.Second))
+			return b.reserve(0, t0.Add(2*time.Second))
+		}, time.Second},
+		{"earlier pause is ignored", func() time.Duration {
+			b.pause(t0.Add(time.Second))
+			return b.reserve(0, t0.Add(2*time.Second))
+		}, time.Second},
+	}
+
+	for _, step := range steps {
+		if got := step.do(); got != step.want {
+			t.Errorf("%s: wait = %v, want %v", step.name, got, step.want)
+		}
+	}
+
+	if b.refund(100, t0.Add(10*time.Second)); b.level != b.size {
+		t.Errorf("refund overflowed the bucket: level = %v, size = %v", b.level, b.size)
+	}
+}
+
+func TestBucketWithoutRateOnlyPauses(t *testing.T) {
+	t0 := time.Now()
+	b := newBucket(0, 1)
+	for i := 0; i < 5; i++ {
+		if wait := b.reserve(1, t0); wait != 0 {
+			t.Fatalf("reserve %d: wait = %v, want 0", i, wait)
+		}
+	}
+	b.pause(t0.Add(time.Second))
+	if wait := b.reserve(1, t0); wait != time.Second {
+		t.Errorf("paused wait = %v, want 1s", wait)
+	}
+}
+
+func TestTuneKey(t *testing.T) {
+	l := newRateLimiter(RateLimit{PerKey: Rate{RequestsPerSecond: 100}})
+	key := "Bearer k"
+	rate := func() float64 { return l.scopes("", key)[1].requests.rate }
+
+	l.tuneKey(key, &KeyInfo{RateLimit: &KeyRateLimit{Requests: 20, Interval: "10s"}})
+	if got := rate(); got != 2 {
+		t.Fatalf("rate = %v, want 2", got)
+	}
+	if size := l.scopes("", key)[1].requests.size; size != 20 {
+		t.Errorf("size = %v, want 20", size)
+	}
+
+	until := time.Now().Add(time.Hour)
+	l.scopes("", key)[1].requests.pause(until)
+
+	ignored := []*KeyInfo{
+		nil,
+		{},
+		{RateLimit: &KeyRateLimit{Requests: 1000, Interval: "1s"}}, // looser than the current budget
+		{RateLimit: &KeyRateLimit{Requests: -1, Interval: "10s"}},
+		{RateLimit: &KeyRateLimit{Requests: 1, Interval: "soon"}},
+	}
+	for _, info := range ignored {
+		l.tuneKey(key, info)
+		if got := rate(); got != 2 {
+			t.Errorf("tuneKey(%+v) changed the rate to %v", info, got)
+		}
+	}
+
+	l.tuneKey(key, &KeyInfo{RateLimit: &KeyRateLimit{Requests: 1, Interval: "1s"}})
+	if got := rate(); got != 1 {
+		t.Errorf("rate = %v, want 1", got)
+	}
+	if paused := l.scopes("", key)[1].requests.paused; !paused.Equal(until) {
+		t.Errorf("tuning dropped the pause: %v, want %v", paused, until)
+	}
+}
+
+func TestRateLimitEachRetryAttempt(t *testing.T) {
+	var attempts atomic.Int32
+	var first, second time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/key" {
+			w.Write([]byte(`{"data":{}}`))
+			return
+		}
+		if attempts.Add(1) == 1 {
+			first = time.Now()
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", fmt.Sprint(time.Now().Add(200*time.Millisecond).UnixMilli()))
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		second = time.Now()
+		w.Write([]byte(`{"id":"ok"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("key",
+		WithBaseURL(srv.URL),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 1, InitialBackoff: time.Millisecond}),
+		WithRateLimit(RateLimit{AutoTune: true}),
+	)
+
+	if _, err := c.CreateChatCompletion(context.Background(), ChatCompletionRequest{Model: "m"}); err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Fatalf("attempts = %d, want 2", got)
+	}
+	if gap := second.Sub(first); gap < 150*time.Millisecond {
+		t.Errorf("retry sent after %v, before the rate limit reset", gap)
+	}
+}
+
+func TestRateLimitSettlesStreamUsage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("data: {\"id\":\"s\"}\n\ndata: {\"id\":\"s\",\"usage\":{\"total_tokens\":10}}\n\ndata: [DONE]\n\n"))
+	}))
+	defer srv.Close()
+
+	// Each call reserves about 105 tokens of a 120 tokens per minute budget,
+	// so the second call only proceeds if the first was settled at 10.
+	c := NewClient("key", WithBaseURL(srv.URL), WithRateLimit(RateLimit{PerModel: Rate{TokensPerMinute: 120}}))
+	req := ChatCompletionRequest{Model: "m", MaxTokens: 100}
+
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		stream, err := c.CreateChatCompletionStream(ctx, req)
+		if err != nil {
+			cancel()
+			t.Fatalf("stream %d: %v", i, err)
+		}
+		for {
+			if _, err := stream.Recv(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("Recv: %v", err)
+			}
+		}
+		stream.Close()
+		cancel()
+	}
+}
@@ -0,0 +1,193 @@
+package openrouter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy configures how failed requests are retried.
+// The zero value disables retries.
+type RetryPolicy struct {
+	// Maximum number of retries after the first attempt.
+	MaxRetries int
+
+	// Delay before the first retry.
+	InitialBackoff time.Duration
+
+	// Upper bound for any single delay, including one requested via Retry-After.
+	MaxBackoff time.Duration
+
+	// Factor the delay grows by after each retry (defaults to 2).
+	Multiplier float64
+
+	// Fraction of the delay to randomize, between 0 and 1.
+	Jitter float64
+
+	// Optional hook called before each retry with the upcoming attempt number
+	// (starting at 2), the delay, and the error that triggered it.
+	OnRetry func(attempt int, wait time.Duration, err error)
+}
+
+// DefaultRetryPolicy is a reasonable policy for most workloads.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:     3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     30 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// WithRetryPolicy enables retries of rate-limited, server-side and transient
+// network failures. Only idempotent calls (GET, HEAD and DELETE), chat
+// completions and key updates are retried; see AllowRetry for other calls.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+type allowRetryKey struct{}
+
+// AllowRetry marks ctx so that non-idempotent calls made with it, such as
+// KeysService.Create, are retried under the client's RetryPolicy too. A
+// retried call may take effect more than once.
+func AllowRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allowRetryKey{}, true)
+}
+
+// backoff returns the delay before the given retry (1-based).
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	mult := p.Multiplier
+	if mult <= 0 {
+		mult = 2
+	}
+	wait := float64(p.InitialBackoff) * math.Pow(mult, float64(retry-1))
+	if p.Jitter > 0 {
+		wait += wait * p.Jitter * (2*rand.Float64() - 1)
+	}
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return time.Duration(wait)
+}
+
+// -----------------------------------------------------------------------------
+// Internal Helpers
+// -----------------------------------------------------------------------------
+
+// do executes req, retrying according to the client's RetryPolicy. If limited
+// is not nil, every attempt waits for the rate limiter and reports back to it.
+// On success the caller owns the response body. Non-2xx responses are
+// returned as *APIError.
+func (c *Client) do(req *http.Request, limited *limitedCall) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", err)
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+
+		if limited != nil {
+			if err := limited.before(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := c.httpClient.Do(req)
+		if err == nil && res.StatusCode >= 200 && res.StatusCode < 300 {
+			if limited != nil {
+				limited.after(res, nil)
+			}
+			return res, nil
+		}
+
+		var retryAfter time.Duration
+		if err != nil {
+			err = fmt.Errorf("failed to execute request: %w", err)
+		} else {
+			apiErr := newAPIError(res)
+			res.Body.Close()
+			retryAfter = apiErr.RetryAfter
+			err = apiErr
+		}
+		if limited != nil {
+			limited.after(nil, err)
+		}
+
+		if attempt > c.retry.MaxRetries || !canRetry(req) || !isRetryable(ctx, err) {
+			return nil, err
+		}
+
+		wait := c.retry.backoff(attempt)
+		if retryAfter > wait {
+			wait = retryAfter
+			if c.retry.MaxBackoff > 0 && wait > c.retry.MaxBackoff {
+				wait = c.retry.MaxBackoff
+			}
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return nil, err
+		}
+
+		if c.retry.OnRetry != nil {
+			c.retry.OnRetry(attempt+1, wait, err)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// canRetry reports whether req may be sent again: it is idempotent or its
+// context was marked with AllowRetry.
+func canRetry(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete:
+		return true
+	}
+	return req.Context().Value(allowRetryKey{}) != nil
+}
+
+// isRetryable reports whether a failed attempt is worth retrying.
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests:
+			return true
+		}
+		return apiErr.StatusCode >= 500 && apiErr.StatusCode != http.StatusNotImplemented
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,67 @@
+package openrouter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryOnlyIdempotentCalls(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	policy := RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond}
+	c := NewClient("key", WithBaseURL(srv.URL), WithRetryPolicy(policy))
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+		want int32
+	}{
+		{"GET", func() error { _, err := c.ListModels(ctx); return err }, 3},
+		{"DELETE", func() error { return c.Keys.Delete(ctx, "hash") }, 3},
+		{"key update", func() error {
+			_, err := c.Keys.Disable(ctx, "hash")
+			return err
+		}, 3},
+		{"other PATCH", func() error {
+			req, err := c.newRequest(ctx, http.MethodPatch, "/other", nil)
+			if err != nil {
+				return err
+			}
+			return c.sendRequest(req, nil)
+		}, 1},
+		{"chat completion", func() error {
+			_, err := c.CreateChatCompletion(ctx, ChatCompletionRequest{Model: "m"})
+			return err
+		}, 3},
+		{"POST /keys", func() error {
+			_, err := c.Keys.Create(ctx, CreateKeyRequest{Name: "k"})
+			return err
+		}, 1},
+		{"POST /keys with AllowRetry", func() error {
+			_, err := c.Keys.Create(AllowRetry(ctx), CreateKeyRequest{Name: "k"})
+			return err
+		}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls.Store(0)
+			if err := tt.call(); err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := calls.Load(); got != tt.want {
+				t.Errorf("sent %d requests, want %d", got, tt.want)
+			}
+		})
+	}
+}
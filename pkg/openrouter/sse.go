@@ -0,0 +1,148 @@
+package openrouter
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StreamEvent is a single Server-Sent Event read from a streaming response.
+type StreamEvent struct {
+	// Event type from the "event:" field; empty means the default "message".
+	Event string
+
+	// Last event ID seen on the stream, from the "id:" field.
+	ID string
+
+	// Payload from the "data:" fields, joined with newlines.
+	Data string
+
+	// Reconnection time requested via the "retry:" field, if any.
+	Retry time.Duration
+
+	// Heartbeat is true for ":"-prefixed comment lines between events, which
+	// OpenRouter sends as keep-alives (e.g. ": OPENROUTER PROCESSING").
+	Heartbeat bool
+
+	// Comment text of a heartbeat, without the leading colon.
+	Comment string
+}
+
+// sseReader decodes a text/event-stream body as described by the
+// WHATWG Server-Sent Events specification.
+type sseReader struct {
+	rd     *bufio.Reader
+	line   []byte
+	skipLF bool // last line ended with CR; drop a following LF
+	bomOK  bool // leading BOM already checked
+
+	lastID string
+}
+
+func newSSEReader(r io.Reader) *sseReader {
+	return &sseReader{rd: bufio.NewReader(r)}
+}
+
+// readLine returns the next line without its terminator. CRLF, LF and CR are
+// all accepted, and lines may be of any length.
+func (r *sseReader) readLine() ([]byte, error) {
+	r.line = r.line[:0]
+	for {
+		b, err := r.rd.ReadByte()
+		if err != nil {
+			if err == io.EOF && len(r.line) > 0 {
+				return r.line, nil
+			}
+			return nil, err
+		}
+
+		if r.skipLF {
+			r.skipLF = false
+			if b == '\n' {
+				continue
+			}
+		}
+
+		switch b {
+		case '\n':
+			return r.line, nil
+		case '\r':
+			r.skipLF = true
+			return r.line, nil
+		}
+		r.line = append(r.line, b)
+	}
+}
+
+// next returns the next dispatched event or heartbeat.
+func (r *sseReader) next() (*StreamEvent, error) {
+	var (
+		data    strings.Builder
+		hasData bool
+		event   string
+		retry   time.Duration
+	)
+
+	for {
+		line, err := r.readLine()
+		if err != nil {
+			// Be lenient with servers that close without a trailing blank line.
+			if err == io.EOF && hasData {
+				return &StreamEvent{Event: event, ID: r.lastID, Data: data.String(), Retry: retry}, nil
+			}
+			return nil, err
+		}
+
+		if !r.bomOK {
+			r.bomOK = true
+			line = bytes.TrimPrefix(line, []byte("\xEF\xBB\xBF"))
+		}
+
+		// A blank line dispatches the pending event.
+		if len(line) == 0 {
+			if !hasData {
+				event, retry = "", 0
+				continue
+			}
+			return &StreamEvent{Event: event, ID: r.lastID, Data: data.String(), Retry: retry}, nil
+		}
+
+		// Comments are surfaced as heartbeats between events and ignored
+		// inside one, so a partial event is never lost.
+		if line[0] == ':' {
+			if hasData || event != "" || retry != 0 {
+				continue
+			}
+			comment := strings.TrimSpace(string(line[1:]))
+			return &StreamEvent{ID: r.lastID, Heartbeat: true, Comment: comment}, nil
+		}
+
+		field, value := line, []byte(nil)
+		if i := bytes.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], line[i+1:]
+			value = bytes.TrimPrefix(value, []byte(" "))
+		}
+
+		switch string(field) {
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.Write(value)
+			hasData = true
+		case "event":
+			event = string(value)
+		case "id":
+			if bytes.IndexByte(value, 0) < 0 {
+				r.lastID = string(value)
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(string(value), 10, 63); err == nil {
+				retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package openrouter
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSSEReader(t *testing.T) {
+	long := strings.Repeat("x", 100<<10)
+
+	tests := []struct {
+		name  string
+		input string
+		want  []StreamEvent
+	}{
+		{
+			name:  "LF",
+			input: "data: a\n\ndata: b\n\n",
+			want:  []StreamEvent{{Data: "a"}, {Data: "b"}},
+		},
+		{
+			name:  "CRLF",
+			input: "data: a\r\n\r\ndata: b\r\n\r\n",
+			want:  []StreamEvent{{Data: "a"}, {Data: "b"}},
+		},
+		{
+			name:  "lone CR",
+			input: "data: a\r\rdata: b\r\r",
+			want:  []StreamEvent{{Data: "a"}, {Data: "b"}},
+		},
+		{
+			name:  "BOM",
+			input: "\xEF\xBB\xBFdata: a\n\n",
+			want:  []StreamEvent{{Data: "a"}},
+		},
+		{
+			name:  "multi-line data",
+			input: "data: {\"a\":\ndata: 1}\n\n",
+			want:  []StreamEvent{{Data: "{\"a\":\n1}"}},
+		},
+		{
+			name:  "comment inside event",
+			input: "data: {\"a\":\n: keepalive\ndata: 1}\n\n",
+			want:  []StreamEvent{{Data: "{\"a\":\n1}"}},
+		},
+		{
+			name:  "comment between events",
+			input: ": OPENROUTER PROCESSING\n\ndata: a\n\n",
+			want: []StreamEvent{
+				{Heartbeat: true, Comment: "OPENROUTER PROCESSING"},
+				{Data: "a"},
+			},
+		},
+		{
+			name:  "field without colon",
+			input: "data\ndata\n\n",
+			want:  []StreamEvent{{Data: "\n"}},
+		},
+		{
+			name:  "no space after colon",
+			input: "data:a\n\n",
+			want:  []StreamEvent{{Data: "a"}},
+		},
+		{
+			name:  "event, id and retry",
+			input: "event: ping\nid: 7\nretry: 1500\ndata: a\n\ndata: b\n\n",
+			want: []StreamEvent{
+				{Event: "ping", ID: "7", Retry: 1500 * time.Millisecond, Data: "a"},
+				{ID: "7", Data: "b"},
+			},
+		},
+		{
+			name:  "unknown field and event without data",
+			input: "foo: bar\n\nevent: x\n\ndata: a\n\n",
+			want:  []StreamEvent{{Data: "a"}},
+		},
+		{
+			name:  "line larger than 64 KiB",
+			input: "data: " + long + "\n\n",
+			want:  []StreamEvent{{Data: long}},
+		},
+		{
+			name:  "missing trailing blank line",
+			input: "data: a",
+			want:  []StreamEvent{{Data: "a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newSSEReader(strings.NewReader(tt.input))
+
+			var got []StreamEvent
+			for {
+				ev, err := r.next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("next: %v", err)
+				}
+				got = append(got, *ev)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %+v, want %+v", truncate(got), truncate(tt.want))
+			}
+		})
+	}
+}
+
+// truncate shortens event data so failures stay readable.
+func truncate(events []StreamEvent) []StreamEvent {
+	out := make([]StreamEvent, len(events))
+	for i, ev := range events {
+		if len(ev.Data) > 32 {
+			ev.Data = ev.Data[:32] + "..."
+		}
+		out[i] = ev
+	}
+	return out
+}
+
+func TestStreamRecvCommentInsideEvent(t *testing.T) {
+	body := "data: {\"id\":\n: keepalive\ndata: \"x\"}\n\ndata: [DONE]\n\n"
+	s := newChatCompletionStream(io.NopCloser(strings.NewReader(body)))
+	defer s.Close()
+
+	resp, err := s.Recv()
+	if err != nil {
+		t.Fatalf("Recv: %v", err)
+	}
+	if resp.ID != "x" {
+		t.Errorf("ID = %q, want %q", resp.ID, "x")
+	}
+	if _, err := s.Recv(); err != io.EOF {
+		t.Errorf("second Recv error = %v, want io.EOF", err)
+	}
+}
@@ -0,0 +1,180 @@
+package openrouter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/david22573/openrouter-api-go/pkg/jsonschema"
+)
+
+// Validator may be implemented by structured output types to add checks that
+// a JSON Schema cannot express. CreateStructured calls it after decoding.
+type Validator interface {
+	Validate() error
+}
+
+// StructuredOption configures CreateStructured.
+type StructuredOption func(*structuredConfig)
+
+type structuredConfig struct {
+	name        string
+	description string
+	maxRepairs  int
+}
+
+// WithSchemaName sets the json_schema name (defaults to the Go type name).
+func WithSchemaName(name string) StructuredOption {
+	return func(c *structuredConfig) {
+		c.name = name
+	}
+}
+
+// WithSchemaDescription sets the json_schema description.
+func WithSchemaDescription(description string) StructuredOption {
+	return func(c *structuredConfig) {
+		c.description = description
+	}
+}
+
+// WithRepairAttempts re-prompts the model with the validation error up to n
+// times when its answer cannot be decoded or fails validation.
+func WithRepairAttempts(n int) StructuredOption {
+	return func(c *structuredConfig) {
+		c.maxRepairs = n
+	}
+}
+
+// NewJSONSchemaFormat builds a strict json_schema ResponseFormat from the type of v.
+func NewJSONSchemaFormat(name string, v interface{}) (*ResponseFormat, error) {
+	schema, err := (&jsonschema.Reflector{Strict: true}).Reflect(v)
+	if err != nil {
+		return nil, err
+	}
+	return newSchemaFormat(name, "", schema)
+}
+
+// CreateStructured requests a completion constrained to the JSON Schema of T,
+// decodes the answer into T and validates it against the schema and, if T
+// implements Validator, its Validate method. The last response is returned
+// alongside any error.
+func CreateStructured[T any](ctx context.Context, client *Client, req ChatCompletionRequest, opts ...StructuredOption) (T, *ChatCompletionResponse, error) {
+	var zero T
+
+	cfg := structuredConfig{name: schemaName(reflect.TypeFor[T]())}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	schema, err := (&jsonschema.Reflector{Strict: true}).ReflectType(reflect.TypeFor[T]())
+	if err != nil {
+		return zero, nil, err
+	}
+	req.ResponseFormat, err = newSchemaFormat(cfg.name, cfg.description, schema)
+	if err != nil {
+		return zero, nil, err
+	}
+	req.Messages = append([]ChatMessage(nil), req.Messages...)
+
+	for attempt := 0; ; attempt++ {
+		resp, err := client.CreateChatCompletion(ctx, req)
+		if err != nil {
+			return zero, resp, err
+		}
+
+		content, err := firstContent(resp)
+		if err != nil {
+			return zero, resp, err
+		}
+
+		result, err := decodeStructured[T](schema, content)
+		if err == nil {
+			return result, resp, nil
+		}
+		if attempt >= cfg.maxRepairs {
+			return zero, resp, err
+		}
+
+		req.Messages = append(req.Messages,
+			ChatMessage{Role: "assistant", Content: content},
+			ChatMessage{Role: "user", Content: fmt.Sprintf(
+				"Your previous response was invalid: %v. Reply again with only JSON that matches the schema.", err)},
+		)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Internal Helpers
+// -----------------------------------------------------------------------------
+
+var (
+	schemaNameInvalid = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+	codeFence         = regexp.MustCompile("(?s)^```[a-zA-Z]*\\s*(.*?)\\s*```$")
+)
+
+func newSchemaFormat(name, description string, schema *jsonschema.Schema) (*ResponseFormat, error) {
+	raw, err := schema.RawMessage()
+	if err != nil {
+		return nil, err
+	}
+	return &ResponseFormat{
+		Type: "json_schema",
+		JSONSchema: &JSONSchemaFormat{
+			Name:        name,
+			Description: description,
+			Strict:      schema.Type == "object",
+			Schema:      raw,
+		},
+	}, nil
+}
+
+// schemaName derives a valid json_schema name from a Go type.
+func schemaName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	name := schemaNameInvalid.ReplaceAllString(t.Name(), "_")
+	if name == "" {
+		return "response"
+	}
+	return name
+}
+
+func firstContent(resp *ChatCompletionResponse) (string, error) {
+	if len(resp.Choices) == 0 || resp.Choices[0].Message == nil {
+		return "", fmt.Errorf("structured output: response has no message")
+	}
+	content, ok := resp.Choices[0].Message.Content.(string)
+	if !ok || strings.TrimSpace(content) == "" {
+		return "", fmt.Errorf("structured output: response has no text content")
+	}
+	return content, nil
+}
+
+// decodeStructured validates content against schema and decodes it into T.
+// Markdown code fences some models add around JSON are stripped.
+func decodeStructured[T any](schema *jsonschema.Schema, content string) (T, error) {
+	var result T
+
+	content = strings.TrimSpace(content)
+	if m := codeFence.FindStringSubmatch(content); m != nil {
+		content = m[1]
+	}
+
+	if err := schema.ValidateJSON([]byte(content)); err != nil {
+		return result, fmt.Errorf("structured output does not match schema: %w", err)
+	}
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return result, fmt.Errorf("failed to decode structured output: %w", err)
+	}
+	if v, ok := interface{}(&result).(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return result, fmt.Errorf("structured output failed validation: %w", err)
+		}
+	}
+
+	return result, nil
+}
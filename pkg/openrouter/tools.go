@@ -0,0 +1,254 @@
+package openrouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/david22573/openrouter-api-go/pkg/jsonschema"
+)
+
+// defaultMaxToolIterations bounds RunWithTools when MaxIterations is unset.
+const defaultMaxToolIterations = 10
+
+var (
+	// ErrMaxIterations is returned by RunWithTools when the model keeps
+	// calling tools after MaxIterations completions.
+	ErrMaxIterations = errors.New("tool runner: maximum iterations reached")
+
+	// ErrCostLimitExceeded is returned by RunWithTools when the accumulated
+	// cost reaches MaxCost.
+	ErrCostLimitExceeded = errors.New("tool runner: cost limit exceeded")
+)
+
+// ToolHandler executes a tool call. It receives the raw JSON arguments produced
+// by the model and returns the content of the resulting tool message.
+type ToolHandler func(ctx context.Context, arguments json.RawMessage) (string, error)
+
+// NewFunctionTool builds a function Tool whose parameters schema is generated
+// from the type of params, typically a zero value of the arguments struct.
+func NewFunctionTool(name, description string, params interface{}) (Tool, error) {
+	schema, err := jsonschema.Reflect(params)
+	if err != nil {
+		return Tool{}, err
+	}
+	raw, err := schema.RawMessage()
+	if err != nil {
+		return Tool{}, err
+	}
+
+	return Tool{
+		Type: "function",
+		Function: ToolFunction{
+			Name:        name,
+			Description: description,
+			Parameters:  raw,
+		},
+	}, nil
+}
+
+// ToolRunner runs the call/execute/append loop for tool-calling conversations.
+type ToolRunner struct {
+	client *Client
+	tools  map[string]registeredTool
+	order  []string
+
+	// Maximum number of completions per run (defaults to 10).
+	MaxIterations int
+
+	// Maximum accumulated cost of a run in USD; zero means no limit.
+	// Setting it turns on usage accounting for the requests.
+	MaxCost float64
+}
+
+type registeredTool struct {
+	tool    Tool
+	handler ToolHandler
+}
+
+// ToolRunResult is the outcome of RunWithTools.
+type ToolRunResult struct {
+	// The last completion received.
+	Response *ChatCompletionResponse
+
+	// The full conversation, including assistant and tool messages.
+	Messages []ChatMessage
+
+	// Number of completions made.
+	Iterations int
+
+	// Accumulated cost reported in usage, in USD.
+	Cost float64
+}
+
+// NewToolRunner creates a ToolRunner that sends requests through client.
+func NewToolRunner(client *Client) *ToolRunner {
+	return &ToolRunner{
+		client: client,
+		tools:  make(map[string]registeredTool),
+	}
+}
+
+// Register adds a function tool. parameters is its JSON Schema object.
+func (r *ToolRunner) Register(name, description string, parameters json.RawMessage, handler ToolHandler) error {
+	if name == "" {
+		return fmt.Errorf("tool name is required")
+	}
+	if handler == nil {
+		return fmt.Errorf("tool %s: handler is required", name)
+	}
+	if _, exists := r.tools[name]; exists {
+		return fmt.Errorf("tool %s is already registered", name)
+	}
+	if len(parameters) == 0 {
+		parameters = json.RawMessage(`{"type":"object","properties":{}}`)
+	}
+
+	r.tools[name] = registeredTool{
+		tool: Tool{
+			Type: "function",
+			Function: ToolFunction{
+				Name:        name,
+				Description: description,
+				Parameters:  parameters,
+			},
+		},
+		handler: handler,
+	}
+	r.order = append(r.order, name)
+	return nil
+}
+
+// Tools returns the registered tool definitions in registration order.
+func (r *ToolRunner) Tools() []Tool {
+	tools := make([]Tool, 0, len(r.order))
+	for _, name := range r.order {
+		tools = append(tools, r.tools[name].tool)
+	}
+	return tools
+}
+
+// RunWithTools sends req, executes any tool calls in the response, appends the
+// results as tool messages and repeats until the model gives a final answer.
+// Registered tools missing from req.Tools are added. Tool calls of one turn
+// run concurrently unless req.ParallelToolCalls is false.
+//
+// On ErrMaxIterations, ErrCostLimitExceeded or an API error the result so far
+// is returned with the error.
+func (r *ToolRunner) RunWithTools(ctx context.Context, req ChatCompletionRequest) (*ToolRunResult, error) {
+	maxIterations := r.MaxIterations
+	if maxIterations <= 0 {
+		maxIterations = defaultMaxToolIterations
+	}
+
+	req.Tools = r.mergeTools(req.Tools)
+	if r.MaxCost > 0 {
+		req.Usage = &UsageConfig{Include: true}
+	}
+	result := &ToolRunResult{
+		Messages: append([]ChatMessage(nil), req.Messages...),
+	}
+
+	for result.Iterations < maxIterations {
+		req.Messages = result.Messages
+		resp, err := r.client.CreateChatCompletion(ctx, req)
+		if err != nil {
+			return result, err
+		}
+		result.Iterations++
+		result.Response = resp
+		result.Cost += resp.Usage.BilledCost()
+
+		if len(resp.Choices) == 0 || resp.Choices[0].Message == nil {
+			return result, fmt.Errorf("tool runner: response has no message")
+		}
+		msg := *resp.Choices[0].Message
+		result.Messages = append(result.Messages, msg)
+
+		if len(msg.ToolCalls) == 0 {
+			return result, nil
+		}
+		if r.MaxCost > 0 && result.Cost >= r.MaxCost {
+			return result, ErrCostLimitExceeded
+		}
+
+		parallel := req.ParallelToolCalls == nil || *req.ParallelToolCalls
+		result.Messages = append(result.Messages, r.execute(ctx, msg.ToolCalls, parallel)...)
+	}
+
+	return result, ErrMaxIterations
+}
+
+// mergeTools appends registered tools that are not already declared.
+func (r *ToolRunner) mergeTools(tools []Tool) []Tool {
+	declared := make(map[string]bool, len(tools))
+	for _, t := range tools {
+		declared[t.Function.Name] = true
+	}
+	merged := append([]Tool(nil), tools...)
+	for _, t := range r.Tools() {
+		if !declared[t.Function.Name] {
+			merged = append(merged, t)
+		}
+	}
+	return merged
+}
+
+// execute runs the tool calls and returns one tool message per call, in order.
+// Handler failures are reported to the model rather than aborting the run.
+func (r *ToolRunner) execute(ctx context.Context, calls []ToolCall, parallel bool) []ChatMessage {
+	results := make([]ChatMessage, len(calls))
+
+	run := func(i int, call ToolCall) {
+		content, err := r.call(ctx, call)
+		if err != nil {
+			content = fmt.Sprintf("error: %v", err)
+		}
+		results[i] = ChatMessage{
+			Role:       "tool",
+			Content:    content,
+			Name:       call.Function.Name,
+			ToolCallID: call.ID,
+		}
+	}
+
+	if !parallel || len(calls) == 1 {
+		for i, call := range calls {
+			run(i, call)
+		}
+		return results
+	}
+
+	var wg sync.WaitGroup
+	for i, call := range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			run(i, call)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (r *ToolRunner) call(ctx context.Context, call ToolCall) (content string, err error) {
+	registered, ok := r.tools[call.Function.Name]
+	if !ok {
+		return "", fmt.Errorf("unknown tool %q", call.Function.Name)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("tool %s panicked: %v", call.Function.Name, p)
+		}
+	}()
+
+	args := json.RawMessage(call.Function.Arguments)
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+	return registered.handler(ctx, args)
+}
@@ -0,0 +1,43 @@
+package openrouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRunWithToolsCostLimit(t *testing.T) {
+	var requests []ChatCompletionRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatCompletionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req)
+
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","tool_calls":[
+			{"id":"1","type":"function","function":{"name":"ping","arguments":"{}"}}]}}],
+			"usage":{"cost":0.6}}`))
+	}))
+	defer srv.Close()
+
+	runner := NewToolRunner(NewClient("key", WithBaseURL(srv.URL)))
+	runner.MaxCost = 1
+	runner.Register("ping", "", nil, func(ctx context.Context, args json.RawMessage) (string, error) {
+		return "pong", nil
+	})
+
+	result, err := runner.RunWithTools(context.Background(), ChatCompletionRequest{Model: "m"})
+	if !errors.Is(err, ErrCostLimitExceeded) {
+		t.Fatalf("err = %v, want ErrCostLimitExceeded", err)
+	}
+	if result.Iterations != 2 || result.Cost != 1.2 {
+		t.Errorf("iterations = %d, cost = %v; want 2 and 1.2", result.Iterations, result.Cost)
+	}
+	for i, req := range requests {
+		if req.Usage == nil || !req.Usage.Include {
+			t.Errorf("request %d does not include usage accounting", i)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package tokenizer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Encoding is a byte-level BPE vocabulary such as cl100k_base or o200k_base.
+type Encoding struct {
+	name  string
+	ranks map[string]int
+	split func(string) []string
+}
+
+// LoadEncoding reads a vocabulary in the tiktoken format: one token per line,
+// base64-encoded, followed by a space and its rank. Encodings named
+// "o200k_*" use the o200k_base pre-tokenizer, all others that of cl100k_base.
+func LoadEncoding(name string, r io.Reader) (*Encoding, error) {
+	enc := &Encoding{name: name, ranks: make(map[string]int), split: split}
+	if strings.HasPrefix(name, "o200k") {
+		enc.split = splitO200K
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		fields := bytes.Fields(scanner.Bytes())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s: line %d: expected token and rank", name, line)
+		}
+		token, err := base64.StdEncoding.DecodeString(string(fields[0]))
+		if err != nil {
+			return nil, fmt.Errorf("%s: line %d: %w", name, line, err)
+		}
+		rank, err := strconv.Atoi(string(fields[1]))
+		if err != nil {
+			return nil, fmt.Errorf("%s: line %d: %w", name, line, err)
+		}
+		enc.ranks[string(token)] = rank
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	return enc, nil
+}
+
+// Name returns the encoding name.
+func (e *Encoding) Name() string {
+	return e.name
+}
+
+// Encode returns the token ranks for text. Special tokens are not recognized.
+// It fails if the vocabulary is incomplete and lacks a byte of text.
+func (e *Encoding) Encode(text string) ([]int, error) {
+	var tokens []int
+	for _, piece := range e.split(text) {
+		if rank, ok := e.ranks[piece]; ok {
+			tokens = append(tokens, rank)
+			continue
+		}
+		for _, part := range e.bytePairMerge(piece) {
+			rank, ok := e.ranks[part]
+			if !ok {
+				return nil, fmt.Errorf("%s: no token for %q", e.name, part)
+			}
+			tokens = append(tokens, rank)
+		}
+	}
+	return tokens, nil
+}
+
+// Count returns the number of tokens in text. Parts missing from an
+// incomplete vocabulary are counted as one token per byte.
+func (e *Encoding) Count(text string) int {
+	n := 0
+	for _, piece := range e.split(text) {
+		if _, ok := e.ranks[piece]; ok {
+			n++
+			continue
+		}
+		for _, part := range e.bytePairMerge(piece) {
+			if _, ok := e.ranks[part]; ok {
+				n++
+			} else {
+				n += len(part)
+			}
+		}
+	}
+	return n
+}
+
+// bytePairMerge splits piece into parts by repeatedly merging the adjacent
+// pair with the lowest rank.
+func (e *Encoding) bytePairMerge(piece string) []string {
+	// parts[i] is the start offset of the i-th part; the last entry is len(piece).
+	parts := make([]int, len(piece)+1)
+	for i := range parts {
+		parts[i] = i
+	}
+
+	for len(parts) > 2 {
+		best, bestRank := -1, math.MaxInt
+		for i := 0; i+2 < len(parts); i++ {
+			if rank, ok := e.ranks[piece[parts[i]:parts[i+2]]]; ok && rank < bestRank {
+				best, bestRank = i, rank
+			}
+		}
+		if best < 0 {
+			break
+		}
+		parts = append(parts[:best+1], parts[best+2:]...)
+	}
+
+	out := make([]string, 0, len(parts)-1)
+	for i := 0; i+1 < len(parts); i++ {
+		out = append(out, piece[parts[i]:parts[i+1]])
+	}
+	return out
+}
+
+// -----------------------------------------------------------------------------
+// Registry
+// -----------------------------------------------------------------------------
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]*Encoding)
+)
+
+// RegisterEncoding makes enc available under its name (e.g. "cl100k_base",
+// "o200k_base"). Estimators for GPT-family models use a registered encoding
+// for exact counts and fall back to a heuristic otherwise.
+func RegisterEncoding(enc *Encoding) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[enc.name] = enc
+}
+
+// GetEncoding returns a registered encoding, or nil if none is registered.
+func GetEncoding(name string) *Encoding {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registry[name]
+}
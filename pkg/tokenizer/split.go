@@ -0,0 +1,235 @@
+package tokenizer
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// split breaks text into pre-tokens following the cl100k_base pattern:
+//
+//	(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}|
+//	 ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+(?!\S)|\s+
+//
+// The pattern relies on a lookahead that Go's regexp package does not support,
+// so it is implemented by hand. BPE merges never cross pre-token boundaries.
+func split(text string) []string {
+	return splitWith(text, matchAt)
+}
+
+// splitO200K breaks text into pre-tokens following the o200k_base pattern:
+//
+//	[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?|
+//	[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?|
+//	\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n/]*|\s*[\r\n]+|\s+(?!\S)|\s+
+//
+// Unlike cl100k_base it splits words at case changes, keeps contractions
+// attached to the word and does not match them on their own.
+func splitO200K(text string) []string {
+	return splitWith(text, matchAtO200K)
+}
+
+func splitWith(text string, match func(text string, i int) int) []string {
+	var pieces []string
+	for i := 0; i < len(text); {
+		n := match(text, i)
+		pieces = append(pieces, text[i:i+n])
+		i += n
+	}
+	return pieces
+}
+
+// matchAt returns the length of the pre-token starting at text[i].
+func matchAt(text string, i int) int {
+	r, size := utf8.DecodeRuneInString(text[i:])
+
+	// Contractions: 's 't 're 've 'm 'll 'd
+	if n := contraction(text[i:]); n > 0 {
+		return n
+	}
+
+	// Optional leading non-letter/number, then letters
+	if isLetter(r) {
+		return size + runLen(text[i+size:], isLetter)
+	}
+	if r != '\r' && r != '\n' && !isNumber(r) {
+		if next, nsize := utf8.DecodeRuneInString(text[i+size:]); isLetter(next) {
+			return size + nsize + runLen(text[i+size+nsize:], isLetter)
+		}
+	}
+
+	// Up to three digits
+	if isNumber(r) {
+		return digitsAt(text, i)
+	}
+
+	if n := punctAt(text, i, isNewline); n > 0 {
+		return n
+	}
+	return spaceAt(text, i)
+}
+
+// matchAtO200K returns the length of the o200k_base pre-token starting at
+// text[i].
+func matchAtO200K(text string, i int) int {
+	r, size := utf8.DecodeRuneInString(text[i:])
+
+	// Optional leading non-letter/number, then a cased word, tried in the
+	// order the regexp alternatives would be.
+	prefixes := []int{0}
+	if r != '\r' && r != '\n' && !isLetter(r) && !isNumber(r) {
+		prefixes = []int{size, 0}
+	}
+	for _, word := range []func(string) int{lowerWord, upperWord} {
+		for _, p := range prefixes {
+			if n := word(text[i+p:]); n > 0 {
+				n += p
+				return n + contraction(text[i+n:])
+			}
+		}
+	}
+
+	// Up to three digits
+	if isNumber(r) {
+		return digitsAt(text, i)
+	}
+
+	if n := punctAt(text, i, func(r rune) bool { return isNewline(r) || r == '/' }); n > 0 {
+		return n
+	}
+	return spaceAt(text, i)
+}
+
+// lowerWord matches [\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+.
+func lowerWord(s string) int {
+	upper := runLen(s, isUpperClass)
+	if lower := runLen(s[upper:], isLowerClass); lower > 0 {
+		return upper + lower
+	}
+	// Backtrack: the upper run must end with a rune of the lower class.
+	for n := upper; n > 0; {
+		r, size := utf8.DecodeLastRuneInString(s[:n])
+		if isLowerClass(r) {
+			return n
+		}
+		n -= size
+	}
+	return 0
+}
+
+// upperWord matches [\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*.
+func upperWord(s string) int {
+	upper := runLen(s, isUpperClass)
+	if upper == 0 {
+		return 0
+	}
+	return upper + runLen(s[upper:], isLowerClass)
+}
+
+// digitsAt matches \p{N}{1,3}.
+func digitsAt(text string, i int) int {
+	n := 0
+	for k := 0; k < 3; k++ {
+		r, size := utf8.DecodeRuneInString(text[i+n:])
+		if !isNumber(r) {
+			break
+		}
+		n += size
+	}
+	return n
+}
+
+// punctAt matches an optional space, a punctuation run and a trailing run of
+// runes matching tail.
+func punctAt(text string, i int, tail func(rune) bool) int {
+	r, size := utf8.DecodeRuneInString(text[i:])
+	start := 0
+	if r == ' ' {
+		if next, _ := utf8.DecodeRuneInString(text[i+size:]); isPunct(next) {
+			start = size
+		}
+	}
+	if start == 0 && !isPunct(r) {
+		return 0
+	}
+	n := start + runLen(text[i+start:], isPunct)
+	return n + runLen(text[i+n:], tail)
+}
+
+// spaceAt matches \s*[\r\n]+|\s+(?!\S)|\s+.
+func spaceAt(text string, i int) int {
+	_, size := utf8.DecodeRuneInString(text[i:])
+	ws := runLen(text[i:], unicode.IsSpace)
+	for k := ws - 1; k >= 0; k-- {
+		if isNewline(rune(text[i+k])) {
+			return k + 1
+		}
+	}
+	if i+ws == len(text) {
+		return ws
+	}
+	if ws > 1 {
+		_, last := utf8.DecodeLastRuneInString(text[i : i+ws])
+		return ws - last
+	}
+	if ws == 1 {
+		return ws
+	}
+
+	// Unreachable for valid input; consume a single rune to make progress.
+	return size
+}
+
+// contraction matches 's 't 're 've 'm 'll 'd at the start of s. The
+// alternatives are tried in order, as a regexp would.
+func contraction(s string) int {
+	if len(s) == 0 || s[0] != '\'' {
+		return 0
+	}
+	for _, c := range []string{"s", "t", "re", "ve", "m", "ll", "d"} {
+		if len(s) > len(c) && equalFoldASCII(s[1:1+len(c)], c) {
+			return 1 + len(c)
+		}
+	}
+	return 0
+}
+
+func equalFoldASCII(a, b string) bool {
+	for i := 0; i < len(a); i++ {
+		if a[i]|0x20 != b[i]|0x20 {
+			return false
+		}
+	}
+	return true
+}
+
+// runLen returns the byte length of the leading run of runes matching f.
+func runLen(s string, f func(rune) bool) int {
+	n := 0
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if !f(r) {
+			break
+		}
+		n += size
+	}
+	return n
+}
+
+func isLetter(r rune) bool  { return unicode.IsLetter(r) }
+func isNumber(r rune) bool  { return unicode.IsNumber(r) }
+func isNewline(r rune) bool { return r == '\r' || r == '\n' }
+
+// isUpperClass matches [\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}].
+func isUpperClass(r rune) bool {
+	return unicode.In(r, unicode.Lu, unicode.Lt, unicode.Lm, unicode.Lo, unicode.M)
+}
+
+// isLowerClass matches [\p{Ll}\p{Lm}\p{Lo}\p{M}].
+func isLowerClass(r rune) bool {
+	return unicode.In(r, unicode.Ll, unicode.Lm, unicode.Lo, unicode.M)
+}
+
+// isPunct matches [^\s\p{L}\p{N}].
+func isPunct(r rune) bool {
+	return r != utf8.RuneError && !unicode.IsSpace(r) && !isLetter(r) && !isNumber(r)
+}
@@ -0,0 +1,260 @@
+// Package tokenizer estimates prompt token counts offline, keyed on the
+// tokenizer names reported in openrouter.ModelArchitecture.Tokenizer.
+//
+// No vocabularies are bundled. GPT-family models are counted exactly only after
+// the matching tiktoken vocabulary (cl100k_base or o200k_base) has been loaded
+// with LoadEncoding and registered with RegisterEncoding. Every other family,
+// and GPT without a vocabulary, uses a rough per-tokenizer heuristic; use
+// Estimator.Exact to tell the two apart.
+package tokenizer
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/david22573/openrouter-api-go/pkg/openrouter"
+)
+
+// Counter counts the tokens in a piece of text.
+type Counter interface {
+	Count(text string) int
+}
+
+// family holds the per-tokenizer constants used for estimation.
+type family struct {
+	// Average ASCII characters per token within a word.
+	charsPerToken float64
+
+	// Tokens per non-ASCII rune (CJK text is close to one token per rune).
+	tokensPerRune float64
+
+	// Overhead per message (role, separators) and for priming the reply.
+	tokensPerMessage int
+	tokensPerName    int
+	replyPriming     int
+
+	// Fixed cost of an image part at low and default detail.
+	imageLowTokens  int
+	imageHighTokens int
+}
+
+// Approximate constants per tokenizer family. They are not derived from a
+// measured corpus, so treat heuristic counts as rough estimates.
+var families = map[string]family{
+	"gpt":      {charsPerToken: 4.2, tokensPerRune: 0.9, tokensPerMessage: 3, tokensPerName: 1, replyPriming: 3, imageLowTokens: 85, imageHighTokens: 765},
+	"claude":   {charsPerToken: 3.5, tokensPerRune: 1.1, tokensPerMessage: 4, tokensPerName: 1, replyPriming: 3, imageLowTokens: 1600, imageHighTokens: 1600},
+	"llama3":   {charsPerToken: 4.2, tokensPerRune: 0.9, tokensPerMessage: 4, tokensPerName: 1, replyPriming: 4, imageLowTokens: 1600, imageHighTokens: 1600},
+	"llama2":   {charsPerToken: 3.3, tokensPerRune: 1.3, tokensPerMessage: 5, tokensPerName: 1, replyPriming: 4, imageLowTokens: 1600, imageHighTokens: 1600},
+	"mistral":  {charsPerToken: 3.6, tokensPerRune: 1.2, tokensPerMessage: 4, tokensPerName: 1, replyPriming: 3, imageLowTokens: 1024, imageHighTokens: 1024},
+	"gemini":   {charsPerToken: 4.0, tokensPerRune: 0.8, tokensPerMessage: 4, tokensPerName: 1, replyPriming: 3, imageLowTokens: 258, imageHighTokens: 258},
+	"qwen":     {charsPerToken: 4.0, tokensPerRune: 0.7, tokensPerMessage: 4, tokensPerName: 1, replyPriming: 3, imageLowTokens: 1024, imageHighTokens: 1024},
+	"deepseek": {charsPerToken: 3.9, tokensPerRune: 0.7, tokensPerMessage: 4, tokensPerName: 1, replyPriming: 3, imageLowTokens: 1024, imageHighTokens: 1024},
+	"other":    {charsPerToken: 3.6, tokensPerRune: 1.0, tokensPerMessage: 4, tokensPerName: 1, replyPriming: 3, imageLowTokens: 1024, imageHighTokens: 1024},
+}
+
+const (
+	// Fixed cost of declaring any tools, and per declared tool.
+	toolsOverhead = 12
+	toolOverhead  = 8
+)
+
+// familyName maps an OpenRouter tokenizer name (e.g. "GPT", "Llama3",
+// "Mistral") or encoding name (e.g. "cl100k_base") to a family key.
+func familyName(tokenizer string) string {
+	name := strings.ToLower(tokenizer)
+	switch {
+	case name == "gpt" || strings.HasSuffix(name, "_base"):
+		return "gpt"
+	case strings.HasPrefix(name, "claude"):
+		return "claude"
+	case strings.HasPrefix(name, "llama2"):
+		return "llama2"
+	case strings.HasPrefix(name, "llama"):
+		return "llama3"
+	case strings.HasPrefix(name, "mistral"):
+		return "mistral"
+	case strings.HasPrefix(name, "gemini"):
+		return "gemini"
+	case strings.HasPrefix(name, "qwen"):
+		return "qwen"
+	case strings.HasPrefix(name, "deepseek"):
+		return "deepseek"
+	}
+	return "other"
+}
+
+// -----------------------------------------------------------------------------
+// Estimator
+// -----------------------------------------------------------------------------
+
+// Estimator estimates prompt tokens for one tokenizer.
+type Estimator struct {
+	family  family
+	counter Counter
+}
+
+// New returns an Estimator for an OpenRouter tokenizer name.
+// For "GPT", the o200k_base encoding is used if registered, then cl100k_base;
+// without either it falls back to the heuristic.
+func New(tokenizer string) *Estimator {
+	return newEstimator(tokenizer, "o200k_base", "cl100k_base")
+}
+
+// ForModel returns an Estimator for the model's tokenizer, choosing between
+// cl100k_base and o200k_base for GPT models based on the model ID.
+func ForModel(m openrouter.Model) *Estimator {
+	if familyName(m.Architecture.Tokenizer) == "gpt" && usesCL100K(m.ID) {
+		return newEstimator(m.Architecture.Tokenizer, "cl100k_base", "o200k_base")
+	}
+	return New(m.Architecture.Tokenizer)
+}
+
+func newEstimator(tokenizer string, encodings ...string) *Estimator {
+	name := familyName(tokenizer)
+	e := &Estimator{family: families[name]}
+
+	if name == "gpt" {
+		if strings.HasSuffix(strings.ToLower(tokenizer), "_base") {
+			encodings = append([]string{tokenizer}, encodings...)
+		}
+		for _, enc := range encodings {
+			if registered := GetEncoding(enc); registered != nil {
+				e.counter = registered
+				break
+			}
+		}
+	}
+	if e.counter == nil {
+		e.counter = heuristic{e.family}
+	}
+
+	return e
+}
+
+// usesCL100K reports whether a GPT model predates the o200k_base encoding.
+func usesCL100K(id string) bool {
+	id = strings.ToLower(id)
+	return strings.Contains(id, "gpt-3.5") || strings.HasSuffix(id, "gpt-4") ||
+		strings.Contains(id, "gpt-4-") || strings.Contains(id, "gpt-4:")
+}
+
+// Exact reports whether the Estimator counts with a registered BPE
+// vocabulary rather than the heuristic. Message and tool overheads are
+// approximate either way.
+func (e *Estimator) Exact() bool {
+	_, ok := e.counter.(*Encoding)
+	return ok
+}
+
+// CountText counts the tokens in text.
+func (e *Estimator) CountText(text string) int {
+	return e.counter.Count(text)
+}
+
+// CountMessages counts the tokens of a conversation, including per-message
+// overhead and the priming of the assistant's reply.
+func (e *Estimator) CountMessages(messages []openrouter.ChatMessage) int {
+	n := e.family.replyPriming
+	for _, msg := range messages {
+		n += e.family.tokensPerMessage + e.CountText(msg.Role)
+		if msg.Name != "" {
+			n += e.family.tokensPerName + e.CountText(msg.Name)
+		}
+		n += e.countContent(msg.Content)
+		for _, tc := range msg.ToolCalls {
+			n += e.CountText(tc.Function.Name) + e.CountText(tc.Function.Arguments)
+		}
+		if msg.ToolCallID != "" {
+			n += e.CountText(msg.ToolCallID)
+		}
+	}
+	return n
+}
+
+// CountTools counts the tokens added by tool declarations.
+func (e *Estimator) CountTools(tools []openrouter.Tool) int {
+	if len(tools) == 0 {
+		return 0
+	}
+	n := toolsOverhead
+	for _, tool := range tools {
+		n += toolOverhead +
+			e.CountText(tool.Function.Name) +
+			e.CountText(tool.Function.Description) +
+			e.CountText(string(tool.Function.Parameters))
+	}
+	return n
+}
+
+// CountRequest estimates the prompt tokens of a request.
+func (e *Estimator) CountRequest(req openrouter.ChatCompletionRequest) int {
+	return e.CountMessages(req.Messages) + e.CountTools(req.Tools)
+}
+
+// FitsContext reports whether req, plus its MaxTokens completion budget, fits
+// the model's context length. It also returns the estimated prompt tokens.
+func FitsContext(m openrouter.Model, req openrouter.ChatCompletionRequest) (bool, int) {
+	prompt := ForModel(m).CountRequest(req)
+	if m.ContextLength <= 0 {
+		return true, prompt
+	}
+	return prompt+req.MaxTokens <= m.ContextLength, prompt
+}
+
+func (e *Estimator) countContent(content interface{}) int {
+	switch c := content.(type) {
+	case string:
+		return e.CountText(c)
+	case []openrouter.ContentPart:
+		n := 0
+		for _, part := range c {
+			n += e.countPart(part)
+		}
+		return n
+	}
+	return 0
+}
+
+func (e *Estimator) countPart(part openrouter.ContentPart) int {
+	n := e.CountText(part.Text)
+	if part.ImageURL != nil {
+		if part.ImageURL.Detail == "low" {
+			n += e.family.imageLowTokens
+		} else {
+			n += e.family.imageHighTokens
+		}
+	}
+	return n
+}
+
+// -----------------------------------------------------------------------------
+// Heuristic
+// -----------------------------------------------------------------------------
+
+// heuristic estimates tokens from the pre-token split: each piece costs at
+// least one token, longer words are divided by the family's average word
+// piece length, and non-ASCII runes are counted individually.
+type heuristic struct {
+	family family
+}
+
+func (h heuristic) Count(text string) int {
+	n := 0
+	for _, piece := range split(text) {
+		var ascii, other int
+		for _, r := range piece {
+			if r < utf8.RuneSelf {
+				if !unicode.IsSpace(r) || len(piece) == 1 {
+					ascii++
+				}
+			} else {
+				other++
+			}
+		}
+		est := float64(ascii)/h.family.charsPerToken + float64(other)*h.family.tokensPerRune
+		n += max(1, int(math.Round(est)))
+	}
+	return n
+}
@@ -0,0 +1,109 @@
+package tokenizer
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name  string
+		split func(string) []string
+		input string
+		want  []string
+	}{
+		{"cl100k words", split, "Hello world, it's 12345!", []string{"Hello", " world", ",", " it", "'s", " ", "123", "45", "!"}},
+		{"cl100k case change", split, "HelloWorld", []string{"HelloWorld"}},
+		{"cl100k spaces", split, "a  b\n\n", []string{"a", " ", " b", "\n\n"}},
+		{"o200k words", splitO200K, "Hello world, it's 12345!", []string{"Hello", " world", ",", " it's", " ", "123", "45", "!"}},
+		{"o200k case change", splitO200K, "HelloWorld", []string{"Hello", "World"}},
+		{"o200k acronym", splitO200K, "HTTPServer", []string{"HTTPServer"}},
+		{"o200k upper contraction", splitO200K, "DON'T", []string{"DON'T"}},
+		{"o200k punctuation with slash", splitO200K, "a.//b", []string{"a", ".//", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.split(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("split(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitCoversInput(t *testing.T) {
+	inputs := []string{"", " ", "日本語のテキスト", "été", "\xff\xfe", "x \r\n\ty", "  trailing  "}
+	for _, input := range inputs {
+		for _, f := range []func(string) []string{split, splitO200K} {
+			if got := strings.Join(f(input), ""); got != input {
+				t.Errorf("pieces of %q join to %q", input, got)
+			}
+		}
+	}
+}
+
+// testEncoding builds an encoding from tokens listed in rank order.
+func testEncoding(t *testing.T, name string, tokens ...string) *Encoding {
+	t.Helper()
+	var b strings.Builder
+	for rank, tok := range tokens {
+		fmt.Fprintf(&b, "%s %d\n", base64.StdEncoding.EncodeToString([]byte(tok)), rank)
+	}
+	enc, err := LoadEncoding(name, strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("LoadEncoding: %v", err)
+	}
+	return enc
+}
+
+func TestEncoding(t *testing.T) {
+	enc := testEncoding(t, "test", "a", "b", "c", "ab", "bc", "abc")
+
+	got, err := enc.Encode("abcab")
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if want := []int{5, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Encode = %v, want %v", got, want)
+	}
+
+	if _, err := enc.Encode("abd"); err == nil {
+		t.Error("Encode with an unknown byte: expected an error")
+	}
+
+	counts := map[string]int{"abcab": 2, "abd": 2, "é": 2, "ab ab": 3}
+	for text, want := range counts {
+		if got := enc.Count(text); got != want {
+			t.Errorf("Count(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestLoadEncodingPreTokenizer(t *testing.T) {
+	tokens := []string{"H", "e", "l", "o", "W", "r", "d", "He", "ll", "Hell", "Hello", "Wo", "rl", "Worl", "World", "HelloWorld"}
+
+	if n := testEncoding(t, "cl100k_base", tokens...).Count("HelloWorld"); n != 1 {
+		t.Errorf("cl100k_base count = %d, want 1", n)
+	}
+	if n := testEncoding(t, "o200k_base", tokens...).Count("HelloWorld"); n != 2 {
+		t.Errorf("o200k_base count = %d, want 2", n)
+	}
+}
+
+func TestEstimatorExact(t *testing.T) {
+	if New("Llama3").Exact() {
+		t.Error("Llama3 estimator reports exact counts")
+	}
+
+	RegisterEncoding(testEncoding(t, "test_base", "a", "b", "ab"))
+	e := New("test_base")
+	if !e.Exact() {
+		t.Fatal("estimator with a registered encoding is not exact")
+	}
+	if n := e.CountText("abab"); n != 2 {
+		t.Errorf("CountText = %d, want 2", n)
+	}
+}